@@ -38,8 +38,7 @@ cat <<'B64' | base64 -d > "$img_file"
 iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mP8/x8AAwMCAO7+RvwAAAAASUVORK5CYII=
 B64
 
-go run ./cmd/geda post upload-image --file "$img_file" --alt-vi="Anh tu skill" --alt-en="Image from skill" >/tmp/geda-skill-upload.json
-media_url=$(php -r '$d=json_decode(file_get_contents($argv[1]), true); echo $d["data"]["url"] ?? "";' /tmp/geda-skill-upload.json)
+media_url=$(go run ./cmd/geda post upload-image --file "$img_file" --alt-vi="Anh tu skill" --alt-en="Image from skill" --query=data.url --raw)
 
 slug="skill-post-$(date +%s)"
 payload="/tmp/geda-skill-post-${slug}.json"
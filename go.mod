@@ -1,8 +1,22 @@
 module geda-cli
 
-go 1.26
+go 1.26.0
 
 require (
+	github.com/JohannesKaufmann/html-to-markdown/v2 v2.5.0
+	github.com/alecthomas/chroma/v2 v2.27.0
 	github.com/yuin/goldmark v1.7.13
+	github.com/yuin/goldmark-highlighting/v2 v2.0.0-20230729083705-37449abec8cc
+	github.com/zalando/go-keyring v0.2.8
+	golang.org/x/term v0.46.0
 	gopkg.in/yaml.v3 v3.0.1
 )
+
+require (
+	github.com/JohannesKaufmann/dom v0.3.1 // indirect
+	github.com/danieljoos/wincred v1.2.3 // indirect
+	github.com/dlclark/regexp2/v2 v2.2.1 // indirect
+	github.com/godbus/dbus/v5 v5.2.2 // indirect
+	golang.org/x/net v0.55.0 // indirect
+	golang.org/x/sys v0.48.0 // indirect
+)
@@ -0,0 +1,250 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"geda-cli/internal/output"
+	"geda-cli/pkg/geda"
+)
+
+const (
+	applyCreated     = "created"
+	applyUpdated     = "updated"
+	applyFailed      = "failed"
+	applySkipped     = "skipped"
+	applyWouldCreate = "would_create"
+	applyWouldUpdate = "would_update"
+)
+
+// applyItem is one payload of a bulk apply and, once processed, its line in
+// the report.
+type applyItem struct {
+	Source    string              `json:"source"`
+	Slug      string              `json:"slug,omitempty"`
+	Action    string              `json:"action"`
+	Error     string              `json:"error,omitempty"`
+	ErrorCode string              `json:"error_code,omitempty"`
+	Fields    map[string][]string `json:"fields,omitempty"`
+
+	payload  map[string]any
+	exitCode int
+}
+
+func (item *applyItem) fail(code string, err error) {
+	item.Action = applyFailed
+	item.Error = err.Error()
+	item.ErrorCode = code
+	item.exitCode = ExitValidation
+
+	apiErr := &geda.APIError{}
+	switch {
+	case errors.As(err, &apiErr):
+		item.ErrorCode = apiErr.Code
+		item.Fields = apiErr.Fields
+		item.exitCode = apiExitCode(apiErr)
+	case errors.Is(err, context.Canceled):
+		item.ErrorCode = "canceled"
+		item.exitCode = ExitCanceled
+	case code == "request_failed":
+		item.exitCode = ExitNetwork
+	}
+}
+
+// runResourceApply upserts every payload of a directory or NDJSON stream
+// with a bounded number of requests in flight, then prints a report of what
+// happened to each payload. The exit code is that of the first failed
+// payload.
+func (r Runner) runResourceApply(ctx context.Context, resource string, args []string) int {
+	client, err := r.authenticatedClient()
+	if err != nil {
+		output.PrintError(err.Error(), "not_logged_in", nil, r.Human)
+
+		return ExitAuth
+	}
+
+	fs := flag.NewFlagSet(resource+" apply", flag.ContinueOnError)
+	dir := fs.String("dir", "", "Directory of JSON payload files")
+	fromStdin := fs.Bool("stdin", false, "Read NDJSON payloads from stdin")
+	concurrency := fs.Int("concurrency", 4, "Maximum number of payloads applied at once")
+	continueOnError := fs.Bool("continue-on-error", false, "Keep applying payloads after a failure")
+	if err := fs.Parse(args); err != nil {
+		output.PrintError(err.Error(), "parse_error", nil, r.Human)
+
+		return ExitValidation
+	}
+
+	if (*dir == "") == !*fromStdin {
+		output.PrintError("exactly one of --dir or --stdin is required", "missing_required_flags", nil, r.Human)
+
+		return ExitValidation
+	}
+	if *concurrency < 1 {
+		output.PrintError("concurrency must be at least 1", "invalid_concurrency", nil, r.Human)
+
+		return ExitValidation
+	}
+
+	var items []*applyItem
+	if *fromStdin {
+		items, err = readNDJSONPayloads(os.Stdin)
+	} else {
+		items, err = readPayloadDir(*dir)
+	}
+	if err != nil {
+		output.PrintError("failed to read payloads", "invalid_payload_file", err.Error(), r.Human)
+
+		return ExitValidation
+	}
+
+	service := client.Resource(resourcePlural(resource))
+
+	// A slot is taken before the stop check, so without
+	// --continue-on-error nothing new starts once a failure is known;
+	// payloads already in flight still finish.
+	var stopped atomic.Bool
+	slots := make(chan struct{}, *concurrency)
+	var wg sync.WaitGroup
+	for _, item := range items {
+		slots <- struct{}{}
+		if stopped.Load() || ctx.Err() != nil {
+			break
+		}
+
+		if item.Action == applyFailed {
+			if !*continueOnError {
+				stopped.Store(true)
+			}
+			<-slots
+
+			continue
+		}
+
+		wg.Go(func() {
+			defer func() { <-slots }()
+
+			r.applyPayload(ctx, service, item)
+			if item.Action == applyFailed && !*continueOnError {
+				stopped.Store(true)
+			}
+		})
+	}
+	wg.Wait()
+
+	summary := map[string]int{"total": len(items), applyCreated: 0, applyUpdated: 0, applyFailed: 0, applySkipped: 0}
+	exitCode := ExitSuccess
+	for _, item := range items {
+		summary[item.Action]++
+		if item.Action == applyFailed && exitCode == ExitSuccess {
+			exitCode = item.exitCode
+		}
+	}
+
+	report := map[string]any{
+		"data":    items,
+		"summary": summary,
+	}
+	if r.DryRun {
+		report["dry_run"] = true
+	}
+
+	if err := output.Print(report, r.outputOptions("")); err != nil {
+		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+
+	return exitCode
+}
+
+func (r Runner) applyPayload(ctx context.Context, service *geda.ResourceService[map[string]any], item *applyItem) {
+	remote, err := fetchRemote(ctx, service, item.Slug)
+	if err != nil {
+		item.fail("request_failed", err)
+
+		return
+	}
+
+	switch {
+	case r.DryRun && remote != nil:
+		item.Action = applyWouldUpdate
+	case r.DryRun:
+		item.Action = applyWouldCreate
+	case remote != nil:
+		_, _, err = service.Update(ctx, item.Slug, item.payload)
+		item.Action = applyUpdated
+	default:
+		_, _, err = service.Create(ctx, item.payload)
+		item.Action = applyCreated
+	}
+	if err != nil {
+		item.fail("request_failed", err)
+	}
+}
+
+// readPayloadDir reads the *.json files directly inside dir, in name order.
+// A file that cannot be decoded becomes a failed item rather than an error.
+func readPayloadDir(dir string) ([]*applyItem, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []*applyItem{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".json") {
+			continue
+		}
+
+		filePath := filepath.Join(dir, entry.Name())
+		payload, err := readJSONFile(filePath)
+		items = append(items, newApplyItem(filePath, payload, err))
+	}
+
+	return items, nil
+}
+
+// readNDJSONPayloads reads one JSON object per line, skipping blank lines.
+func readNDJSONPayloads(file *os.File) ([]*applyItem, error) {
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	items := []*applyItem{}
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		payload := map[string]any{}
+		err := json.Unmarshal([]byte(text), &payload)
+		items = append(items, newApplyItem(fmt.Sprintf("stdin:%d", line), payload, err))
+	}
+
+	return items, scanner.Err()
+}
+
+func newApplyItem(source string, payload map[string]any, err error) *applyItem {
+	item := &applyItem{Source: source, Action: applySkipped, payload: payload}
+	if err != nil {
+		item.fail("invalid_payload_file", err)
+
+		return item
+	}
+
+	item.Slug = getString(payload, "slug")
+	if item.Slug == "" {
+		item.fail("missing_slug", errors.New("slug is required in JSON payload"))
+	}
+
+	return item
+}
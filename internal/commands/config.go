@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"flag"
+
+	"geda-cli/internal/config"
+	"geda-cli/internal/output"
+)
+
+func (r Runner) runConfig(args []string) int {
+	if len(args) == 0 || args[0] != "resolve" {
+		r.printConfigUsage()
+
+		return ExitValidation
+	}
+
+	fs := flag.NewFlagSet("config resolve", flag.ContinueOnError)
+	if err := fs.Parse(args[1:]); err != nil {
+		output.PrintError(err.Error(), "parse_error", nil, r.Human)
+
+		return ExitValidation
+	}
+
+	settings, err := r.resolveSettings()
+	if err != nil {
+		output.PrintError("failed to load CLI profile", "load_profile_failed", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+
+	configPath, err := config.Path()
+	if err != nil {
+		output.PrintError("failed to resolve config path", "load_profile_failed", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+
+	token := settings.AccessToken
+	token.Value = config.RedactToken(token.Value)
+
+	data := map[string]any{
+		"config_path":    configPath,
+		"profile":        settingOutput(settings.Profile, "--profile", config.EnvProfile),
+		"profile_exists": settings.Saved != nil,
+		"base_url":       settingOutput(settings.BaseURL, "--base-url", config.EnvBaseURL),
+		"token":          tokenOutput(settings, token),
+		"user_email":     settings.UserEmail,
+	}
+
+	if err := output.Print(map[string]any{"data": data}, r.outputOptions("")); err != nil {
+		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+
+	return ExitSuccess
+}
+
+// settingOutput describes a resolved setting together with the flag or
+// environment variable it came from.
+func settingOutput(setting config.Setting, flagName string, envName string) map[string]any {
+	result := map[string]any{
+		"value":  setting.Value,
+		"source": setting.Source,
+	}
+
+	switch setting.Source {
+	case config.SourceFlag:
+		result["from"] = flagName
+	case config.SourceEnv:
+		result["from"] = envName
+	}
+
+	return result
+}
+
+// tokenOutput is settingOutput for the token, naming the credential store
+// it is kept in. The store is not opened, so its token is not shown.
+func tokenOutput(settings *config.Settings, token config.Setting) map[string]any {
+	result := settingOutput(token, "--token", config.EnvToken)
+	if token.Source == config.SourceCredentialStore {
+		result["from"] = settings.Saved.CredentialStore
+	}
+
+	return result
+}
+
+func (r Runner) printConfigUsage() {
+	output.PrintError("Usage: geda config resolve", "usage", nil, r.Human)
+}
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"geda-cli/internal/diff"
+	"geda-cli/internal/output"
+	"geda-cli/pkg/geda"
+)
+
+func (r Runner) runResourceDiff(ctx context.Context, resource string, args []string) int {
+	client, err := r.authenticatedClient()
+	if err != nil {
+		output.PrintError(err.Error(), "not_logged_in", nil, r.Human)
+
+		return ExitAuth
+	}
+
+	fs := flag.NewFlagSet(resource+" diff", flag.ContinueOnError)
+	filePath := fs.String("file", "", "Path to JSON payload file")
+	slugFlag := fs.String("slug", "", "Resource slug override")
+	if err := fs.Parse(args); err != nil {
+		output.PrintError(err.Error(), "parse_error", nil, r.Human)
+
+		return ExitValidation
+	}
+
+	payload, slug, exitCode := r.readResourcePayload(*filePath, *slugFlag)
+	if exitCode != ExitSuccess {
+		return exitCode
+	}
+
+	remote, err := fetchRemote(ctx, client.Resource(resourcePlural(resource)), slug)
+	if err != nil {
+		return r.handleError(err)
+	}
+
+	changes := diff.Compare(remote, payload)
+
+	if r.Human && r.Output == output.FormatJSON && r.Query == "" {
+		if err := diff.Write(os.Stdout, changes, output.ColorEnabled(os.Stdout)); err != nil {
+			output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+
+			return ExitNetwork
+		}
+
+		return ExitSuccess
+	}
+
+	if err := output.Print(map[string]any{
+		"slug":    slug,
+		"exists":  remote != nil,
+		"changes": changes,
+	}, r.outputOptions("")); err != nil {
+		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+
+	return ExitSuccess
+}
+
+// readResourcePayload reads the JSON payload at filePath and the slug it
+// targets, which slugOverride replaces when set. A non-zero exit code means
+// the error has already been printed.
+func (r Runner) readResourcePayload(filePath string, slugOverride string) (map[string]any, string, int) {
+	if filePath == "" {
+		output.PrintError("file is required", "missing_required_flags", nil, r.Human)
+
+		return nil, "", ExitValidation
+	}
+
+	payload, err := readJSONFile(filePath)
+	if err != nil {
+		output.PrintError("failed to read payload file", "invalid_payload_file", err.Error(), r.Human)
+
+		return nil, "", ExitValidation
+	}
+
+	slug := strings.TrimSpace(slugOverride)
+	if slug == "" {
+		slug = getString(payload, "slug")
+	}
+	if slug == "" {
+		output.PrintError("slug is required in --slug or JSON payload", "missing_slug", nil, r.Human)
+
+		return nil, "", ExitValidation
+	}
+
+	return payload, slug, ExitSuccess
+}
+
+// fetchRemote returns the record stored under slug, or nil when the API has
+// none.
+func fetchRemote(ctx context.Context, service *geda.ResourceService[map[string]any], slug string) (map[string]any, error) {
+	record, _, err := service.Get(ctx, slug)
+	if errors.Is(err, geda.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return map[string]any{}, nil
+	}
+
+	return *record, nil
+}
+
+// confirm asks question on w and reports whether the answer read from in was
+// yes. Anything else, including end of input, is a no.
+func confirm(in io.Reader, w io.Writer, question string) bool {
+	fmt.Fprintf(w, "%s [y/N] ", question)
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
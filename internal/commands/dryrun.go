@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"geda-cli/internal/output"
+	"geda-cli/pkg/geda"
+)
+
+// plannedRequest is a mutating request that --dry-run reports instead of
+// sending.
+type plannedRequest struct {
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	Payload  any    `json:"payload,omitempty"`
+	// Note explains a request whose outcome depends on an earlier one.
+	Note string `json:"note,omitempty"`
+}
+
+// printDryRun prints the requests a command would send and reports success
+// without sending them.
+func (r Runner) printDryRun(requests ...plannedRequest) int {
+	if requests == nil {
+		requests = []plannedRequest{}
+	}
+
+	if err := output.Print(map[string]any{
+		"dry_run":  true,
+		"requests": requests,
+	}, r.outputOptions("")); err != nil {
+		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+
+	return ExitSuccess
+}
+
+// planUpsert decides between create and update with a read-only lookup of
+// slug.
+func planUpsert[T any](ctx context.Context, service *geda.ResourceService[T], resource string, slug string, payload any) (plannedRequest, error) {
+	_, _, err := service.Get(ctx, slug)
+	if err != nil && !errors.Is(err, geda.ErrNotFound) {
+		return plannedRequest{}, err
+	}
+
+	return upsertRequest(resource, slug, err == nil, payload), nil
+}
+
+// upsertRequest is the PUT or POST an upsert sends, depending on whether
+// the record exists.
+func upsertRequest(resource string, slug string, exists bool, payload any) plannedRequest {
+	if exists {
+		return plannedRequest{Method: http.MethodPut, Endpoint: endpointFor(resource, slug), Payload: payload}
+	}
+
+	return plannedRequest{Method: http.MethodPost, Endpoint: endpointFor(resource, ""), Payload: payload}
+}
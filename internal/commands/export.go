@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	"geda-cli/internal/exporter"
+	"geda-cli/internal/output"
+)
+
+func (r Runner) runExport(ctx context.Context, args []string) int {
+	client, err := r.authenticatedClient()
+	if err != nil {
+		output.PrintError(err.Error(), "not_logged_in", nil, r.Human)
+
+		return ExitAuth
+	}
+
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	dir := fs.String("dir", "", "Directory to write the site to")
+	media := fs.Bool("media", true, "Download referenced media into media/")
+	markdown := fs.Bool("markdown", false, "Also write posts as per-locale Markdown files for post import")
+	if err := fs.Parse(args); err != nil {
+		output.PrintError(err.Error(), "parse_error", nil, r.Human)
+
+		return ExitValidation
+	}
+
+	if strings.TrimSpace(*dir) == "" {
+		output.PrintError("dir is required", "missing_required_flags", nil, r.Human)
+
+		return ExitValidation
+	}
+
+	manifest, err := exporter.Export(ctx, client, exporter.Options{
+		Dir:      *dir,
+		Media:    *media,
+		Markdown: *markdown,
+	})
+	if err != nil {
+		return r.handleError(err)
+	}
+
+	if err := output.Print(map[string]any{
+		"dir":      *dir,
+		"counts":   manifest.Counts,
+		"files":    len(manifest.Files),
+		"warnings": manifest.Warnings,
+	}, r.outputOptions("")); err != nil {
+		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+
+	return ExitSuccess
+}
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"geda-cli/internal/importer"
+	"geda-cli/internal/mediacache"
+	"geda-cli/pkg/geda"
+)
+
+// imageResolver returns the URL to use for each local image of a post,
+// keyed by importer.Image.Path.
+type imageResolver func(ctx context.Context, images []importer.Image) (map[string]string, error)
+
+// mediaUploads looks up images in the media cache of the client's site.
+// With refresh set it uploads every image again, replacing the cached URLs,
+// for when media was deleted on the server.
+type mediaUploads struct {
+	cache   *mediacache.Cache
+	baseURL string
+	refresh bool
+}
+
+func openMediaUploads(client *geda.Client, refresh bool) (*mediaUploads, error) {
+	cachePath, err := mediacache.Path()
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := mediacache.Load(cachePath)
+	if err != nil {
+		return nil, &importError{message: "failed to read media cache", code: "invalid_media_cache", err: err}
+	}
+
+	return &mediaUploads{cache: cache, baseURL: client.BaseURL(), refresh: refresh}, nil
+}
+
+// resolve calls upload for each image whose content is not in the cache.
+func (u *mediaUploads) resolve(images []importer.Image, upload func(image importer.Image) (string, error)) (map[string]string, error) {
+	urls := make(map[string]string, len(images))
+	for _, image := range images {
+		hash, err := mediacache.HashFile(image.Path)
+		if err != nil {
+			return nil, &importError{message: "failed to read local image", code: "invalid_image", err: err}
+		}
+
+		if link, ok := u.cache.Get(u.baseURL, hash); ok && !u.refresh {
+			urls[image.Path] = link
+
+			continue
+		}
+
+		link, err := upload(image)
+		if err != nil {
+			return nil, err
+		}
+
+		u.cache.Put(u.baseURL, hash, link)
+		urls[image.Path] = link
+	}
+
+	return urls, nil
+}
+
+// uploadImages uploads the images of a post through the media library,
+// reusing earlier uploads of the same content to the same site unless
+// refresh is set.
+func uploadImages(client *geda.Client, refresh bool) imageResolver {
+	return func(ctx context.Context, images []importer.Image) (map[string]string, error) {
+		uploads, err := openMediaUploads(client, refresh)
+		if err != nil {
+			return nil, err
+		}
+
+		urls, err := uploads.resolve(images, func(image importer.Image) (string, error) {
+			media, _, err := client.Media.UploadWithAltText(ctx, image.Path, image.AltText)
+			if err != nil {
+				return "", err
+			}
+			if media == nil || media.URL == "" {
+				return "", fmt.Errorf("upload of %s returned no media url", image.Path)
+			}
+
+			return media.URL, nil
+		})
+
+		// Uploads that succeeded are cached even when a later one failed.
+		return urls, errors.Join(err, uploads.cache.Save())
+	}
+}
+
+// planImageUploads records the uploads a post import would make, using a
+// placeholder for the URL of each new upload.
+func planImageUploads(client *geda.Client, refresh bool, requests *[]plannedRequest) imageResolver {
+	return func(_ context.Context, images []importer.Image) (map[string]string, error) {
+		uploads, err := openMediaUploads(client, refresh)
+		if err != nil {
+			return nil, err
+		}
+
+		return uploads.resolve(images, func(image importer.Image) (string, error) {
+			fields := map[string]any{"file": "@" + image.Path}
+			for locale, text := range image.AltText {
+				fields["alt_text["+locale+"]"] = text
+			}
+			*requests = append(*requests, plannedRequest{Method: http.MethodPost, Endpoint: "/api/v1/media", Payload: fields})
+
+			return "<url of uploaded " + image.Path + ">", nil
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"geda-cli/internal/mockserver"
+	"geda-cli/internal/output"
+)
+
+func (r Runner) runMock(ctx context.Context, args []string) int {
+	if len(args) == 0 || args[0] != "serve" {
+		r.printMockUsage()
+
+		return ExitValidation
+	}
+
+	fs := flag.NewFlagSet("mock serve", flag.ContinueOnError)
+	host := fs.String("host", "127.0.0.1", "Address to listen on")
+	port := fs.Int("port", 8000, "Port to listen on (0 picks a free port)")
+	fixtures := fs.String("fixtures", "", "Directory of JSON fixtures to seed from")
+	if err := fs.Parse(args[1:]); err != nil {
+		output.PrintError(err.Error(), "parse_error", nil, r.Human)
+
+		return ExitValidation
+	}
+
+	server := mockserver.New()
+	if *fixtures != "" {
+		if err := server.LoadFixtures(*fixtures); err != nil {
+			output.PrintError("failed to load fixtures", "invalid_fixtures", err.Error(), r.Human)
+
+			return ExitValidation
+		}
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(*host, strconv.Itoa(*port)))
+	if err != nil {
+		output.PrintError("failed to listen", "listen_failed", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+
+	httpServer := &http.Server{
+		Handler:           server,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if err := output.Print(map[string]any{
+		"message":  "Mock geda-web server listening. Press Ctrl-C to stop.",
+		"base_url": "http://" + listener.Addr().String(),
+	}, r.outputOptions("")); err != nil {
+		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-served:
+		output.PrintError("mock server stopped", "serve_failed", err.Error(), r.Human)
+
+		return ExitNetwork
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		output.PrintError("failed to stop mock server", "serve_failed", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+
+	return ExitSuccess
+}
+
+func (r Runner) printMockUsage() {
+	output.PrintError("Usage: geda mock serve [--port=8000] [--host=127.0.0.1] [--fixtures=<dir>]", "usage", nil, r.Human)
+}
@@ -0,0 +1,254 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"geda-cli/internal/diff"
+	"geda-cli/internal/output"
+	"geda-cli/pkg/geda"
+)
+
+// runResourcePatch changes single fields of a record. The record is read
+// first so nested fields such as title.en can be merged into the rest of
+// the translation, and so --if-match can check updated_at. The write also
+// carries --if-match as If-Unmodified-Since, so the server can refuse it
+// when the record changed after the read. Only the touched top-level fields
+// are sent with PATCH; servers that reject PATCH get the whole merged record
+// with PUT instead.
+func (r Runner) runResourcePatch(ctx context.Context, resource string, args []string) int {
+	client, err := r.authenticatedClient()
+	if err != nil {
+		output.PrintError(err.Error(), "not_logged_in", nil, r.Human)
+
+		return ExitAuth
+	}
+
+	fs := flag.NewFlagSet(resource+" patch", flag.ContinueOnError)
+	slug := fs.String("slug", "", "Resource slug")
+	ifMatch := fs.String("if-match", "", "Only apply when the record's updated_at equals this RFC 3339 timestamp")
+	var sets, unsets []string
+	fs.Func("set", "Field assignment path=value, value parsed as JSON when possible (repeatable)", func(value string) error {
+		if !strings.Contains(value, "=") {
+			return fmt.Errorf("expected path=value, got %q", value)
+		}
+
+		sets = append(sets, value)
+
+		return nil
+	})
+	fs.Func("unset", "Field path to clear (repeatable)", func(value string) error {
+		unsets = append(unsets, value)
+
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		output.PrintError(err.Error(), "parse_error", nil, r.Human)
+
+		return ExitValidation
+	}
+
+	if *slug == "" {
+		output.PrintError("slug is required", "missing_required_flags", nil, r.Human)
+
+		return ExitValidation
+	}
+	if len(sets) == 0 && len(unsets) == 0 {
+		output.PrintError("at least one --set or --unset is required", "missing_required_flags", nil, r.Human)
+
+		return ExitValidation
+	}
+
+	var since time.Time
+	if *ifMatch != "" {
+		since, err = time.Parse(time.RFC3339Nano, *ifMatch)
+		if err != nil {
+			output.PrintError("--if-match must be an RFC 3339 timestamp", "invalid_if_match", map[string]any{"if_match": *ifMatch}, r.Human)
+
+			return ExitValidation
+		}
+	}
+
+	service := client.Resource(resourcePlural(resource))
+
+	current, _, err := service.Get(ctx, *slug)
+	if err != nil {
+		return r.handleError(err)
+	}
+
+	remote := map[string]any{}
+	if current != nil {
+		remote = *current
+	}
+
+	// The timestamps are compared as instants, so the same time written
+	// with trailing zeros in the fraction or another offset still matches.
+	if *ifMatch != "" {
+		updatedAt, err := time.Parse(time.RFC3339Nano, getString(remote, "updated_at"))
+		if err != nil {
+			output.PrintError("record has no valid updated_at to check --if-match against", "invalid_updated_at", map[string]any{
+				"updated_at": remote["updated_at"],
+			}, r.Human)
+
+			return ExitValidation
+		}
+
+		if !updatedAt.Equal(since) {
+			output.PrintError("record was changed since it was read", "precondition_failed", map[string]any{
+				"expected_updated_at": *ifMatch,
+				"updated_at":          remote["updated_at"],
+			}, r.Human)
+
+			return ExitConflict
+		}
+	}
+
+	merged := diff.Normalize(remote)
+	touched := []string{}
+	for _, assignment := range sets {
+		fieldPath, value, _ := strings.Cut(assignment, "=")
+		if err := setPath(merged, fieldPath, parseStringToValue(value)); err != nil {
+			output.PrintError(err.Error(), "invalid_field_path", map[string]any{"path": fieldPath}, r.Human)
+
+			return ExitValidation
+		}
+		touched = append(touched, fieldPath)
+	}
+	for _, fieldPath := range unsets {
+		if err := unsetPath(merged, fieldPath); err != nil {
+			output.PrintError(err.Error(), "invalid_field_path", map[string]any{"path": fieldPath}, r.Human)
+
+			return ExitValidation
+		}
+		touched = append(touched, fieldPath)
+	}
+
+	// unsetPath leaves cleared top-level fields as null, since leaving them
+	// out of the request would keep the stored value.
+	patch := map[string]any{}
+	for _, fieldPath := range touched {
+		key, _, _ := strings.Cut(fieldPath, ".")
+		patch[key] = merged[key]
+	}
+
+	note := "sent as PUT with the whole record if the API does not support PATCH"
+	if *ifMatch != "" {
+		unmodifiedSince := since.UTC().Format(http.TimeFormat)
+		ctx = geda.WithHeader(ctx, "If-Unmodified-Since", unmodifiedSince)
+		note += ", with If-Unmodified-Since: " + unmodifiedSince
+	}
+
+	if r.DryRun {
+		return r.printDryRun(plannedRequest{
+			Method:   http.MethodPatch,
+			Endpoint: endpointFor(resource, *slug),
+			Payload:  patch,
+			Note:     note,
+		})
+	}
+
+	_, response, err := service.Patch(ctx, *slug, patch)
+	if unsupported(err) {
+		_, response, err = service.Update(ctx, *slug, diff.WritePayload(merged))
+	}
+	if err != nil {
+		return r.handleError(err)
+	}
+
+	if err := output.Print(response.Body, r.outputOptions(resource)); err != nil {
+		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+
+	return ExitSuccess
+}
+
+// unsupported reports whether err means the API has no route for the
+// method.
+func unsupported(err error) bool {
+	var apiErr *geda.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return apiErr.Status == http.StatusMethodNotAllowed || apiErr.Status == http.StatusNotImplemented
+}
+
+// setPath assigns value at a dotted path, creating objects along the way.
+func setPath(record map[string]any, fieldPath string, value any) error {
+	keys, err := splitPath(fieldPath)
+	if err != nil {
+		return err
+	}
+
+	current := record
+	for i, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			if current[key] != nil {
+				return fmt.Errorf("%s is not an object", strings.Join(keys[:i+1], "."))
+			}
+
+			next = map[string]any{}
+			current[key] = next
+		}
+
+		current = next
+	}
+
+	current[keys[len(keys)-1]] = value
+
+	return nil
+}
+
+// unsetPath removes the field at a dotted path. A top-level field is set to
+// nil instead, so the API is told to clear it.
+func unsetPath(record map[string]any, fieldPath string) error {
+	keys, err := splitPath(fieldPath)
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 1 {
+		record[keys[0]] = nil
+
+		return nil
+	}
+
+	current := record
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		current = next
+	}
+
+	delete(current, keys[len(keys)-1])
+
+	return nil
+}
+
+func splitPath(fieldPath string) ([]string, error) {
+	keys := strings.Split(strings.TrimSpace(fieldPath), ".")
+	for _, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("invalid field path %q", fieldPath)
+		}
+	}
+
+	for _, field := range diff.ServerFields {
+		if keys[0] == field {
+			return nil, fmt.Errorf("%s is set by the server", field)
+		}
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+
+	"geda-cli/internal/config"
+	"geda-cli/internal/credentials"
+	"geda-cli/internal/output"
+)
+
+func (r Runner) runProfile(args []string) int {
+	if len(args) == 0 {
+		r.printProfileUsage()
+
+		return ExitValidation
+	}
+
+	switch args[0] {
+	case "list":
+		file, err := config.LoadFile()
+		if err != nil {
+			output.PrintError("failed to load CLI profile", "load_profile_failed", err.Error(), r.Human)
+
+			return ExitNetwork
+		}
+
+		profiles := make([]map[string]any, 0, len(file.Profiles))
+		for _, name := range file.Names() {
+			profiles = append(profiles, profileSummary(file, name))
+		}
+
+		return r.printProfileOutput(map[string]any{
+			"current_profile": file.CurrentProfile,
+			"data":            profiles,
+		})
+	case "show":
+		fs := flag.NewFlagSet("profile show", flag.ContinueOnError)
+		name := fs.String("name", "", "Profile name (defaults to the selected profile)")
+		if err := fs.Parse(args[1:]); err != nil {
+			output.PrintError(err.Error(), "parse_error", nil, r.Human)
+
+			return ExitValidation
+		}
+
+		file, err := config.LoadFile()
+		if err != nil {
+			output.PrintError("failed to load CLI profile", "load_profile_failed", err.Error(), r.Human)
+
+			return ExitNetwork
+		}
+
+		resolved := *name
+		if resolved == "" {
+			settings, err := r.resolveSettings()
+			if err != nil {
+				output.PrintError("failed to load CLI profile", "load_profile_failed", err.Error(), r.Human)
+
+				return ExitNetwork
+			}
+
+			resolved = settings.Profile.Value
+		}
+		if _, ok := file.Profiles[resolved]; !ok {
+			output.PrintError("profile not found", "profile_not_found", map[string]any{"profile": resolved}, r.Human)
+
+			return ExitValidation
+		}
+
+		return r.printProfileOutput(map[string]any{
+			"data": profileSummary(file, resolved),
+		})
+	case "use", "remove":
+		fs := flag.NewFlagSet("profile "+args[0], flag.ContinueOnError)
+		name := fs.String("name", "", "Profile name")
+		if err := fs.Parse(args[1:]); err != nil {
+			output.PrintError(err.Error(), "parse_error", nil, r.Human)
+
+			return ExitValidation
+		}
+		if *name == "" {
+			output.PrintError("name is required", "missing_required_flags", nil, r.Human)
+
+			return ExitValidation
+		}
+
+		var err error
+		message := ""
+		if args[0] == "use" {
+			err = config.UseProfile(*name)
+			message = "Switched to profile " + *name + "."
+		} else {
+			err = config.RemoveProfile(*name)
+			message = "Profile " + *name + " removed."
+		}
+		if err != nil {
+			return r.handleProfileError(err, *name)
+		}
+
+		return r.printProfileOutput(map[string]any{
+			"message": message,
+			"profile": *name,
+		})
+	case "rename":
+		fs := flag.NewFlagSet("profile rename", flag.ContinueOnError)
+		name := fs.String("name", "", "Current profile name")
+		newName := fs.String("new-name", "", "New profile name")
+		if err := fs.Parse(args[1:]); err != nil {
+			output.PrintError(err.Error(), "parse_error", nil, r.Human)
+
+			return ExitValidation
+		}
+		if *name == "" || *newName == "" {
+			output.PrintError("name and new-name are required", "missing_required_flags", nil, r.Human)
+
+			return ExitValidation
+		}
+
+		if err := config.ValidateProfileName(*newName); err != nil {
+			output.PrintError(err.Error(), "invalid_profile_name", nil, r.Human)
+
+			return ExitValidation
+		}
+
+		if err := config.RenameProfile(*name, *newName); err != nil {
+			return r.handleProfileError(err, *name)
+		}
+
+		return r.printProfileOutput(map[string]any{
+			"message": "Profile " + *name + " renamed to " + *newName + ".",
+			"profile": *newName,
+		})
+	default:
+		output.PrintError("Unknown profile subcommand", "unknown_subcommand", map[string]any{"subcommand": args[0]}, r.Human)
+
+		return ExitValidation
+	}
+}
+
+func (r Runner) handleProfileError(err error, name string) int {
+	switch {
+	case errors.Is(err, config.ErrProfileNotFound):
+		output.PrintError("profile not found", "profile_not_found", map[string]any{"profile": name}, r.Human)
+
+		return ExitValidation
+	case errors.Is(err, config.ErrProfileExists):
+		output.PrintError("profile already exists", "profile_exists", err.Error(), r.Human)
+
+		return ExitValidation
+	default:
+		output.PrintError("failed to save CLI profile", "save_profile_failed", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+}
+
+func (r Runner) printProfileOutput(payload map[string]any) int {
+	if err := output.Print(payload, r.outputOptions("")); err != nil {
+		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+
+	return ExitSuccess
+}
+
+func profileSummary(file *config.File, name string) map[string]any {
+	profile := file.Profiles[name]
+
+	return map[string]any{
+		"name":             name,
+		"base_url":         profile.BaseURL,
+		"user_email":       profile.UserEmail,
+		"last_login_at":    profile.LastLoginAt,
+		"logged_in":        profile.LoggedIn(),
+		"credential_store": credentialStoreName(profile.CredentialStore),
+		"current":          name == file.CurrentProfile,
+	}
+}
+
+func credentialStoreName(name string) string {
+	if name == "" {
+		return credentials.Plaintext
+	}
+
+	return name
+}
+
+func (r Runner) printProfileUsage() {
+	output.PrintError("Usage: geda profile <list|use|show|remove|rename>", "usage", nil, r.Human)
+}
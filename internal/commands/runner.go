@@ -1,20 +1,28 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"geda-cli/internal/config"
+	"geda-cli/internal/credentials"
+	"geda-cli/internal/diff"
 	"geda-cli/internal/httpclient"
 	"geda-cli/internal/importer"
 	"geda-cli/internal/output"
+	"geda-cli/pkg/geda"
 )
 
 const (
@@ -22,20 +30,83 @@ const (
 	ExitValidation = 1
 	ExitAuth       = 2
 	ExitNetwork    = 3
+	// ExitNotFound, ExitConflict, ExitUnprocessable and ExitRateLimited
+	// report 404, 409/412, 422 and 429 API responses.
+	ExitNotFound      = 4
+	ExitConflict      = 5
+	ExitUnprocessable = 6
+	ExitRateLimited   = 7
+	// ExitCanceled follows the shell convention of 128+SIGINT.
+	ExitCanceled = 130
+)
+
+const (
+	envDebug        = "GEDA_DEBUG"
+	envCassette     = "GEDA_CASSETTE"
+	envCassetteMode = "GEDA_CASSETTE_MODE"
 )
 
 type Runner struct {
-	Human bool
+	Human     bool
+	Verbose   bool
+	PrintCurl bool
+	Profile   string
+	BaseURL   string
+	Token     string
+	// DryRun resolves everything read-only and prints the mutating requests
+	// instead of sending them.
+	DryRun bool
+
+	// Output, Columns, Locale, Template, Query and Raw control how
+	// responses are printed, see output.Options.
+	Output   output.Format
+	Columns  []string
+	Locale   string
+	Template string
+	Query    string
+	Raw      bool
+
+	Retries      int
+	RetryMaxWait time.Duration
+	RetryPost    bool
+
+	Timeout        time.Duration
+	ConnectTimeout time.Duration
+	UploadTimeout  time.Duration
+
+	// Cassette records or replays HTTP traffic, see GEDA_CASSETTE.
+	Cassette *httpclient.Cassette
 }
 
 func Run(args []string) int {
-	human, filteredArgs := extractHumanFlag(args)
-	runner := Runner{Human: human}
+	runner, filteredArgs, err := extractGlobalFlags(args)
+	if err != nil {
+		output.PrintError(err.Error(), "parse_error", nil, runner.Human)
+
+		return ExitValidation
+	}
+
+	if path := strings.TrimSpace(os.Getenv(envCassette)); path != "" {
+		mode := httpclient.CassetteMode(strings.TrimSpace(os.Getenv(envCassetteMode)))
+		if mode == "" {
+			mode = httpclient.CassetteReplay
+		}
 
-	return runner.Run(filteredArgs)
+		runner.Cassette, err = httpclient.OpenCassette(path, mode)
+		if err != nil {
+			output.PrintError("failed to open cassette", "invalid_cassette", err.Error(), runner.Human)
+
+			return ExitValidation
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return runner.Run(ctx, filteredArgs)
 }
 
-func (r Runner) Run(args []string) int {
+func (r Runner) Run(ctx context.Context, args []string) int {
 	if len(args) == 0 {
 		r.printUsage()
 
@@ -44,21 +115,35 @@ func (r Runner) Run(args []string) int {
 
 	switch args[0] {
 	case "auth":
-		return r.runAuth(args[1:])
+		return r.runAuth(ctx, args[1:])
 	case "health":
-		return r.runHealth(args[1:])
+		return r.runHealth(ctx, args[1:])
+	case "profile":
+		return r.runProfile(args[1:])
+	case "config":
+		return r.runConfig(args[1:])
 	case "post":
-		return r.runContentResource("post", args[1:])
+		return r.runContentResource(ctx, "post", args[1:])
 	case "category":
-		return r.runContentResource("category", args[1:])
+		return r.runContentResource(ctx, "category", args[1:])
 	case "tag":
-		return r.runContentResource("tag", args[1:])
+		return r.runContentResource(ctx, "tag", args[1:])
 	case "page":
-		return r.runContentResource("page", args[1:])
+		return r.runContentResource(ctx, "page", args[1:])
 	case "product":
-		return r.runContentResource("product", args[1:])
+		return r.runContentResource(ctx, "product", args[1:])
 	case "settings":
-		return r.runSettings(args[1:])
+		return r.runSettings(ctx, args[1:])
+	case "export":
+		return r.runExport(ctx, args[1:])
+	case "status":
+		return r.runStatus(ctx, args[1:])
+	case "pull":
+		return r.runPull(ctx, args[1:])
+	case "push":
+		return r.runPush(ctx, args[1:])
+	case "mock":
+		return r.runMock(ctx, args[1:])
 	default:
 		output.PrintError("Unknown command", "unknown_command", map[string]any{"command": args[0]}, r.Human)
 
@@ -66,7 +151,7 @@ func (r Runner) Run(args []string) int {
 	}
 }
 
-func (r Runner) runAuth(args []string) int {
+func (r Runner) runAuth(ctx context.Context, args []string) int {
 	if len(args) == 0 {
 		r.printAuthUsage()
 
@@ -76,12 +161,13 @@ func (r Runner) runAuth(args []string) int {
 	switch args[0] {
 	case "login":
 		fs := flag.NewFlagSet("auth login", flag.ContinueOnError)
-		baseURL := fs.String("base-url", "", "API base URL, example: http://localhost:8000")
 		email := fs.String("email", "", "User email")
 		password := fs.String("password", "", "User password")
 		device := fs.String("device", "geda-cli", "Device name")
 		otp := fs.String("otp", "", "Two-factor OTP code")
 		recoveryCode := fs.String("recovery-code", "", "Two-factor recovery code")
+		credentialStore := fs.String("credential-store", os.Getenv(config.EnvCredentialStore), "Where to keep the token: "+strings.Join(credentials.Names(), ", "))
+		credentialHelper := fs.String("credential-helper", "", "Credential helper command for --credential-store=helper")
 
 		if err := fs.Parse(args[1:]); err != nil {
 			output.PrintError(err.Error(), "parse_error", nil, r.Human)
@@ -89,43 +175,78 @@ func (r Runner) runAuth(args []string) int {
 			return ExitValidation
 		}
 
-		if *baseURL == "" || *email == "" || *password == "" {
+		settings, err := r.resolveSettings()
+		if err != nil {
+			output.PrintError("failed to load CLI profile", "load_profile_failed", err.Error(), r.Human)
+
+			return ExitNetwork
+		}
+
+		if settings.BaseURL.Value == "" || *email == "" || *password == "" {
 			output.PrintError("base-url, email, and password are required", "missing_required_flags", nil, r.Human)
 
 			return ExitValidation
 		}
 
-		client := httpclient.New(*baseURL, "")
-		response, err := client.Post("/api/v1/auth/login", map[string]any{
-			"email":         *email,
-			"password":      *password,
-			"device_name":   *device,
-			"otp":           emptyToNil(*otp),
-			"recovery_code": emptyToNil(*recoveryCode),
+		store := strings.TrimSpace(*credentialStore)
+		helper := strings.TrimSpace(*credentialHelper)
+		if store == "" && settings.Saved != nil {
+			store = settings.Saved.CredentialStore
+			if helper == "" {
+				helper = settings.Saved.CredentialHelper
+			}
+		}
+		if store != "" && !slices.Contains(credentials.Names(), store) {
+			output.PrintError("unknown credential store", "invalid_credential_store", map[string]any{
+				"credential_store": store,
+				"available":        credentials.Names(),
+			}, r.Human)
+
+			return ExitValidation
+		}
+		if store == credentials.Helper && helper == "" {
+			output.PrintError("credential-helper is required for the helper credential store", "missing_required_flags", nil, r.Human)
+
+			return ExitValidation
+		}
+
+		client := r.newClient(settings.BaseURL.Value, "")
+		result, response, err := client.Auth.Login(ctx, geda.LoginRequest{
+			Email:        *email,
+			Password:     *password,
+			DeviceName:   *device,
+			OTP:          *otp,
+			RecoveryCode: *recoveryCode,
 		})
 		if err != nil {
 			return r.handleError(err)
 		}
 
-		token, _ := response["access_token"].(string)
-		if token == "" {
-			output.PrintError("login response did not include access_token", "invalid_login_response", response, r.Human)
+		if result.AccessToken == "" {
+			output.PrintError("login response did not include access_token", "invalid_login_response", response.Body, r.Human)
 
 			return ExitNetwork
 		}
 
-		if err := config.Save(config.Profile{
-			BaseURL:     strings.TrimRight(*baseURL, "/"),
-			AccessToken: token,
-			UserEmail:   extractUserEmail(response),
-			LastLoginAt: time.Now().UTC().Format(time.RFC3339),
+		userEmail := ""
+		if result.User != nil {
+			userEmail = result.User.Email
+		}
+
+		if err := config.SaveProfile(settings.Profile.Value, config.Profile{
+			BaseURL:          settings.BaseURL.Value,
+			AccessToken:      result.AccessToken,
+			UserEmail:        userEmail,
+			LastLoginAt:      time.Now().UTC().Format(time.RFC3339),
+			CredentialStore:  store,
+			CredentialHelper: helper,
 		}); err != nil {
 			output.PrintError("failed to save CLI profile", "save_profile_failed", err.Error(), r.Human)
 
 			return ExitNetwork
 		}
 
-		if err := output.Print(response, r.Human); err != nil {
+		if err := output.Print(response.Body, r.outputOptions("")); err != nil {
 			output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
 
 			return ExitNetwork
@@ -133,31 +254,37 @@ func (r Runner) runAuth(args []string) int {
 
 		return ExitSuccess
 	case "logout":
-		profile, err := config.Load()
+		settings, err := r.resolveSettings()
 		if err != nil {
 			output.PrintError("failed to load CLI profile", "load_profile_failed", err.Error(), r.Human)
 
 			return ExitNetwork
 		}
-		if profile == nil || profile.AccessToken == "" || profile.BaseURL == "" {
+		token, err := settings.Token()
+		if err != nil {
+			output.PrintError("failed to load CLI profile", "load_profile_failed", err.Error(), r.Human)
+
+			return ExitNetwork
+		}
+		if token == "" || settings.BaseURL.Value == "" {
 			output.PrintError("you are not logged in", "not_logged_in", nil, r.Human)
 
 			return ExitAuth
 		}
 
-		client := httpclient.New(profile.BaseURL, profile.AccessToken)
-		response, err := client.Post("/api/v1/auth/logout", map[string]any{})
+		client := r.newClient(settings.BaseURL.Value, token)
+		response, err := client.Auth.Logout(ctx)
 		if err != nil {
 			return r.handleError(err)
 		}
 
-		if err := config.Clear(); err != nil {
+		if err := config.ClearProfile(settings.Profile.Value); err != nil {
 			output.PrintError("failed to clear CLI profile", "clear_profile_failed", err.Error(), r.Human)
 
 			return ExitNetwork
 		}
 
-		if err := output.Print(response, r.Human); err != nil {
+		if err := output.Print(response.Body, r.outputOptions("")); err != nil {
 			output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
 
 			return ExitNetwork
@@ -172,12 +299,12 @@ func (r Runner) runAuth(args []string) int {
 			return ExitAuth
 		}
 
-		response, err := client.Get("/api/v1/auth/me")
+		_, response, err := client.Auth.Me(ctx)
 		if err != nil {
 			return r.handleError(err)
 		}
 
-		if err := output.Print(response, r.Human); err != nil {
+		if err := output.Print(response.Body, r.outputOptions("")); err != nil {
 			output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
 
 			return ExitNetwork
@@ -191,7 +318,7 @@ func (r Runner) runAuth(args []string) int {
 	}
 }
 
-func (r Runner) runHealth(args []string) int {
+func (r Runner) runHealth(ctx context.Context, args []string) int {
 	if len(args) == 0 || args[0] != "check" {
 		r.printHealthUsage()
 
@@ -199,33 +326,32 @@ func (r Runner) runHealth(args []string) int {
 	}
 
 	fs := flag.NewFlagSet("health check", flag.ContinueOnError)
-	baseURL := fs.String("base-url", "", "API base URL")
 	if err := fs.Parse(args[1:]); err != nil {
 		output.PrintError(err.Error(), "parse_error", nil, r.Human)
 
 		return ExitValidation
 	}
 
-	resolvedBaseURL := strings.TrimSpace(*baseURL)
-	if resolvedBaseURL == "" {
-		profile, err := config.Load()
-		if err == nil && profile != nil {
-			resolvedBaseURL = profile.BaseURL
-		}
+	settings, err := r.resolveSettings()
+	if err != nil {
+		output.PrintError("failed to load CLI profile", "load_profile_failed", err.Error(), r.Human)
+
+		return ExitNetwork
 	}
+	resolvedBaseURL := settings.BaseURL.Value
 	if resolvedBaseURL == "" {
 		output.PrintError("base-url is required when not logged in", "missing_base_url", nil, r.Human)
 
 		return ExitValidation
 	}
 
-	client := httpclient.New(resolvedBaseURL, "")
-	response, err := client.Get("/api/v1/health")
+	client := r.newClient(resolvedBaseURL, "")
+	_, response, err := client.Health(ctx)
 	if err != nil {
 		return r.handleError(err)
 	}
 
-	if err := output.Print(response, r.Human); err != nil {
+	if err := output.Print(response.Body, r.outputOptions("")); err != nil {
 		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
 
 		return ExitNetwork
@@ -234,7 +360,7 @@ func (r Runner) runHealth(args []string) int {
 	return ExitSuccess
 }
 
-func (r Runner) runContentResource(resource string, args []string) int {
+func (r Runner) runContentResource(ctx context.Context, resource string, args []string) int {
 	if len(args) == 0 {
 		r.printResourceUsage(resource)
 
@@ -243,13 +369,19 @@ func (r Runner) runContentResource(resource string, args []string) int {
 
 	switch args[0] {
 	case "list":
-		return r.runResourceList(resource, args[1:])
+		return r.runResourceList(ctx, resource, args[1:])
 	case "get":
-		return r.runResourceGet(resource, args[1:])
+		return r.runResourceGet(ctx, resource, args[1:])
 	case "delete":
-		return r.runResourceDelete(resource, args[1:])
+		return r.runResourceDelete(ctx, resource, args[1:])
 	case "upsert":
-		return r.runResourceUpsert(resource, args[1:])
+		return r.runResourceUpsert(ctx, resource, args[1:])
+	case "diff":
+		return r.runResourceDiff(ctx, resource, args[1:])
+	case "patch":
+		return r.runResourcePatch(ctx, resource, args[1:])
+	case "apply":
+		return r.runResourceApply(ctx, resource, args[1:])
 	case "import":
 		if resource != "post" {
 			output.PrintError("import is only supported for post", "invalid_subcommand", nil, r.Human)
@@ -257,7 +389,7 @@ func (r Runner) runContentResource(resource string, args []string) int {
 			return ExitValidation
 		}
 
-		return r.runPostImport(args[1:])
+		return r.runPostImport(ctx, args[1:])
 	case "upload-image":
 		if resource != "post" {
 			output.PrintError("upload-image is only supported for post", "invalid_subcommand", nil, r.Human)
@@ -265,7 +397,7 @@ func (r Runner) runContentResource(resource string, args []string) int {
 			return ExitValidation
 		}
 
-		return r.runPostUploadImage(args[1:])
+		return r.runPostUploadImage(ctx, args[1:])
 	default:
 		output.PrintError("Unknown resource subcommand", "unknown_subcommand", map[string]any{"subcommand": args[0]}, r.Human)
 
@@ -273,7 +405,7 @@ func (r Runner) runContentResource(resource string, args []string) int {
 	}
 }
 
-func (r Runner) runResourceList(resource string, args []string) int {
+func (r Runner) runResourceList(ctx context.Context, resource string, args []string) int {
 	client, err := r.authenticatedClient()
 	if err != nil {
 		output.PrintError(err.Error(), "not_logged_in", nil, r.Human)
@@ -286,34 +418,103 @@ func (r Runner) runResourceList(resource string, args []string) int {
 	status := fs.String("status", "", "Status filter")
 	typeFilter := fs.String("type", "", "Type filter")
 	perPage := fs.Int("per-page", 15, "Items per page")
+	page := fs.Int("page", 1, "Page to fetch, or the first page with --all")
+	all := fs.Bool("all", false, "Fetch every page")
+	limit := fs.Int("limit", 0, "Stop after this many items")
+	ndjson := fs.Bool("ndjson", false, "Print one item per line as pages arrive")
 	if err := fs.Parse(args); err != nil {
 		output.PrintError(err.Error(), "parse_error", nil, r.Human)
 
 		return ExitValidation
 	}
+	if *page < 1 || *perPage < 1 || *limit < 0 {
+		output.PrintError("page and per-page must be positive and limit must not be negative", "invalid_flags", nil, r.Human)
 
-	query := []string{fmt.Sprintf("per_page=%d", *perPage)}
-	if *search != "" {
-		query = append(query, "search="+urlEncode(*search))
+		return ExitValidation
 	}
-	if *status != "" {
-		query = append(query, "status="+urlEncode(*status))
+
+	// A small limit needs no more than one page of that size. Later pages
+	// keep their size, so --page still selects the same items.
+	if *limit > 0 && *limit < *perPage && *page == 1 {
+		*perPage = *limit
 	}
-	if *typeFilter != "" {
-		query = append(query, "type="+urlEncode(*typeFilter))
+
+	service := client.Resource(resourcePlural(resource))
+	opts := &geda.ListOptions{
+		Page:    *page,
+		PerPage: *perPage,
+		Search:  *search,
+		Status:  *status,
+		Type:    *typeFilter,
 	}
 
-	endpoint := fmt.Sprintf("/api/v1/%s", resourcePlural(resource))
-	if len(query) > 0 {
-		endpoint += "?" + strings.Join(query, "&")
+	if !*all && !*ndjson && *limit == 0 {
+		_, response, err := service.List(ctx, opts)
+		if err != nil {
+			return r.handleError(err)
+		}
+
+		if err := output.Print(response.Body, r.outputOptions(resource)); err != nil {
+			output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+
+			return ExitNetwork
+		}
+
+		return ExitSuccess
 	}
 
-	response, err := client.Get(endpoint)
-	if err != nil {
+	pager := service.Pages(opts)
+	items := []any{}
+	count := 0
+	var meta any
+
+	for pager.Next(ctx) {
+		response := pager.Response().Body
+		meta = response["meta"]
+
+		pageItems := pager.RawItems()
+		if _, ok := response["data"].([]any); !ok {
+			// Not a paginated listing: treat the whole response as one item.
+			pageItems = []any{response}
+		}
+
+		for _, item := range pageItems {
+			if *limit > 0 && count >= *limit {
+				break
+			}
+			count++
+
+			if !*ndjson {
+				items = append(items, item)
+
+				continue
+			}
+
+			if err := output.PrintLine(item, r.outputOptions(resource)); err != nil {
+				output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+
+				return ExitNetwork
+			}
+		}
+
+		if !*all || (*limit > 0 && count >= *limit) {
+			break
+		}
+	}
+	if err := pager.Err(); err != nil {
 		return r.handleError(err)
 	}
 
-	if err := output.Print(response, r.Human); err != nil {
+	if *ndjson {
+		return ExitSuccess
+	}
+
+	result := map[string]any{"data": items}
+	if !*all && meta != nil {
+		result["meta"] = meta
+	}
+
+	if err := output.Print(result, r.outputOptions(resource)); err != nil {
 		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
 
 		return ExitNetwork
@@ -322,7 +523,7 @@ func (r Runner) runResourceList(resource string, args []string) int {
 	return ExitSuccess
 }
 
-func (r Runner) runResourceGet(resource string, args []string) int {
+func (r Runner) runResourceGet(ctx context.Context, resource string, args []string) int {
 	client, err := r.authenticatedClient()
 	if err != nil {
 		output.PrintError(err.Error(), "not_logged_in", nil, r.Human)
@@ -343,12 +544,12 @@ func (r Runner) runResourceGet(resource string, args []string) int {
 		return ExitValidation
 	}
 
-	response, err := client.Get(fmt.Sprintf("/api/v1/%s/%s", resourcePlural(resource), *slug))
+	_, response, err := client.Resource(resourcePlural(resource)).Get(ctx, *slug)
 	if err != nil {
 		return r.handleError(err)
 	}
 
-	if err := output.Print(response, r.Human); err != nil {
+	if err := output.Print(response.Body, r.outputOptions(resource)); err != nil {
 		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
 
 		return ExitNetwork
@@ -357,7 +558,7 @@ func (r Runner) runResourceGet(resource string, args []string) int {
 	return ExitSuccess
 }
 
-func (r Runner) runResourceDelete(resource string, args []string) int {
+func (r Runner) runResourceDelete(ctx context.Context, resource string, args []string) int {
 	client, err := r.authenticatedClient()
 	if err != nil {
 		output.PrintError(err.Error(), "not_logged_in", nil, r.Human)
@@ -378,12 +579,21 @@ func (r Runner) runResourceDelete(resource string, args []string) int {
 		return ExitValidation
 	}
 
-	response, err := client.Delete(fmt.Sprintf("/api/v1/%s/%s", resourcePlural(resource), *slug))
+	service := client.Resource(resourcePlural(resource))
+	if r.DryRun {
+		if _, _, err := service.Get(ctx, *slug); err != nil {
+			return r.handleError(err)
+		}
+
+		return r.printDryRun(plannedRequest{Method: http.MethodDelete, Endpoint: endpointFor(resource, *slug)})
+	}
+
+	response, err := service.Delete(ctx, *slug)
 	if err != nil {
 		return r.handleError(err)
 	}
 
-	if err := output.Print(response, r.Human); err != nil {
+	if err := output.Print(response.Body, r.outputOptions(resource)); err != nil {
 		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
 
 		return ExitNetwork
@@ -392,7 +602,7 @@ func (r Runner) runResourceDelete(resource string, args []string) int {
 	return ExitSuccess
 }
 
-func (r Runner) runResourceUpsert(resource string, args []string) int {
+func (r Runner) runResourceUpsert(ctx context.Context, resource string, args []string) int {
 	client, err := r.authenticatedClient()
 	if err != nil {
 		output.PrintError(err.Error(), "not_logged_in", nil, r.Human)
@@ -403,64 +613,81 @@ func (r Runner) runResourceUpsert(resource string, args []string) int {
 	fs := flag.NewFlagSet(resource+" upsert", flag.ContinueOnError)
 	filePath := fs.String("file", "", "Path to JSON payload file")
 	slugFlag := fs.String("slug", "", "Resource slug override")
+	showDiff := fs.Bool("diff", false, "Print the changes against the remote record to stderr before applying them")
+	confirmChanges := fs.Bool("confirm", false, "Ask before applying the changes, implies --diff")
 	if err := fs.Parse(args); err != nil {
 		output.PrintError(err.Error(), "parse_error", nil, r.Human)
 
 		return ExitValidation
 	}
 
-	if *filePath == "" {
-		output.PrintError("file is required", "missing_required_flags", nil, r.Human)
-
-		return ExitValidation
-	}
-
-	payload, err := readJSONFile(*filePath)
-	if err != nil {
-		output.PrintError("failed to read payload file", "invalid_payload_file", err.Error(), r.Human)
-
-		return ExitValidation
-	}
-
-	slug := strings.TrimSpace(*slugFlag)
-	if slug == "" {
-		slug = getString(payload, "slug")
-	}
-	if slug == "" {
-		output.PrintError("slug is required in --slug or JSON payload", "missing_slug", nil, r.Human)
-
-		return ExitValidation
+	payload, slug, exitCode := r.readResourcePayload(*filePath, *slugFlag)
+	if exitCode != ExitSuccess {
+		return exitCode
 	}
 
-	endpoint := fmt.Sprintf("/api/v1/%s/%s", resourcePlural(resource), slug)
+	service := client.Resource(resourcePlural(resource))
 
-	_, err = client.Get(endpoint)
-	if err == nil {
-		response, updateErr := client.Put(endpoint, payload)
-		if updateErr != nil {
-			return r.handleError(updateErr)
+	// With --diff the remote record is fetched once here and decides
+	// between create and update below.
+	checked := *showDiff || *confirmChanges
+	var remote map[string]any
+	if checked {
+		remote, err = fetchRemote(ctx, service, slug)
+		if err != nil {
+			return r.handleError(err)
 		}
 
-		if err := output.Print(response, r.Human); err != nil {
+		changes := diff.Compare(remote, payload)
+		if err := diff.Write(os.Stderr, changes, output.ColorEnabled(os.Stderr)); err != nil {
 			output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
 
 			return ExitNetwork
 		}
 
-		return ExitSuccess
+		question := fmt.Sprintf("Apply %d change(s) to %s %q?", len(changes), resource, slug)
+		// Declining is a normal outcome, not an interrupt, so it exits 0.
+		if *confirmChanges && !r.DryRun && len(changes) > 0 && !confirm(os.Stdin, os.Stderr, question) {
+			if err := output.Print(map[string]any{
+				"message": "upsert aborted",
+				"slug":    slug,
+				"aborted": true,
+			}, r.outputOptions("")); err != nil {
+				output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+
+				return ExitNetwork
+			}
+
+			return ExitSuccess
+		}
 	}
 
-	apiErr := &httpclient.APIError{}
-	if !errors.As(err, &apiErr) || apiErr.Status != 404 {
-		return r.handleError(err)
+	if r.DryRun {
+		request := upsertRequest(resource, slug, remote != nil, payload)
+		if !checked {
+			request, err = planUpsert(ctx, service, resource, slug, payload)
+			if err != nil {
+				return r.handleError(err)
+			}
+		}
+
+		return r.printDryRun(request)
 	}
 
-	response, createErr := client.Post(fmt.Sprintf("/api/v1/%s", resourcePlural(resource)), payload)
-	if createErr != nil {
-		return r.handleError(createErr)
+	var response *geda.Response
+	switch {
+	case !checked:
+		_, response, err = service.Upsert(ctx, slug, payload)
+	case remote != nil:
+		_, response, err = service.Update(ctx, slug, payload)
+	default:
+		_, response, err = service.Create(ctx, payload)
+	}
+	if err != nil {
+		return r.handleError(err)
 	}
 
-	if err := output.Print(response, r.Human); err != nil {
+	if err := output.Print(response.Body, r.outputOptions(resource)); err != nil {
 		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
 
 		return ExitNetwork
@@ -469,7 +696,7 @@ func (r Runner) runResourceUpsert(resource string, args []string) int {
 	return ExitSuccess
 }
 
-func (r Runner) runPostImport(args []string) int {
+func (r Runner) runPostImport(ctx context.Context, args []string) int {
 	client, err := r.authenticatedClient()
 	if err != nil {
 		output.PrintError(err.Error(), "not_logged_in", nil, r.Human)
@@ -478,8 +705,23 @@ func (r Runner) runPostImport(args []string) int {
 	}
 
 	fs := flag.NewFlagSet("post import", flag.ContinueOnError)
-	viPath := fs.String("vi", "", "Vietnamese markdown file")
-	enPath := fs.String("en", "", "English markdown file")
+	files := localeFiles{}
+	fs.Func("vi", "Vietnamese markdown file", files.setter("vi"))
+	fs.Func("en", "English markdown file", files.setter("en"))
+	fs.Func("locale-file", "Markdown file of one locale as locale=path (repeatable)", func(value string) error {
+		locale, filePath, ok := strings.Cut(value, "=")
+		if !ok {
+			return fmt.Errorf("expected locale=path, got %q", value)
+		}
+
+		return files.setter(strings.TrimSpace(locale))(filePath)
+	})
+	required := fs.String("required-locales", strings.Join(importer.DefaultLocales.Required, ","), "Comma-separated locales that must have a file")
+	fallback := fs.String("fallback-locale", "", "Locale whose front matter supplies shared fields (default: first required locale)")
+	singleFile := fs.String("file", "", "Single markdown file holding every locale")
+	uploadLocalImages := fs.Bool("upload-images", true, "Upload images given by a relative path and use their URLs")
+	noMediaCache := fs.Bool("no-media-cache", false, "Upload images again even when the media cache has their URLs")
+	shortcodeDir := fs.String("shortcodes", "", "Directory of custom shortcode templates (<name>.html)")
 	upsert := fs.Bool("upsert", true, "Upsert post by slug")
 	if err := fs.Parse(args); err != nil {
 		output.PrintError(err.Error(), "parse_error", nil, r.Human)
@@ -487,89 +729,290 @@ func (r Runner) runPostImport(args []string) int {
 		return ExitValidation
 	}
 
-	if *viPath == "" || *enPath == "" {
-		output.PrintError("both --vi and --en are required", "missing_required_flags", nil, r.Human)
+	// Remaining arguments are files named like post.ja.md.
+	for _, filePath := range fs.Args() {
+		locale, ok := importer.LocaleFromPath(filePath)
+		if !ok {
+			output.PrintError("cannot detect the locale of "+filePath, "unknown_locale", "name the file <name>.<locale>.md or use --locale-file", r.Human)
 
-		return ExitValidation
+			return ExitValidation
+		}
+
+		if err := files.setter(locale)(filePath); err != nil {
+			output.PrintError(err.Error(), "parse_error", nil, r.Human)
+
+			return ExitValidation
+		}
 	}
 
-	viDoc, err := importer.ParseMarkdownFile(*viPath)
-	if err != nil {
-		output.PrintError("failed to parse Vietnamese markdown", "invalid_markdown", err.Error(), r.Human)
+	options := importer.LocaleOptions{Fallback: strings.TrimSpace(*fallback)}
+	for _, locale := range strings.Split(*required, ",") {
+		if locale = strings.TrimSpace(locale); locale != "" {
+			options.Required = append(options.Required, locale)
+		}
+	}
+
+	var documents map[string]importer.Document
+	switch {
+	case *singleFile != "" && len(files) > 0:
+		output.PrintError("--file cannot be combined with per-locale files", "conflicting_flags", nil, r.Human)
+
+		return ExitValidation
+	case *singleFile != "":
+		documents, err = importer.ParseMultilingualFile(*singleFile)
+		if err != nil {
+			return r.handleImportError(&importError{message: "failed to parse markdown", code: "invalid_markdown", err: err})
+		}
+	case len(files) == 0:
+		output.PrintError("at least one markdown file is required", "missing_required_flags", "use --file, --vi and --en, --locale-file or pass files named <name>.<locale>.md", r.Human)
 
 		return ExitValidation
+	default:
+		documents, err = readLocaleFiles(files)
+		if err != nil {
+			return r.handleImportError(err)
+		}
+	}
+
+	requests := []plannedRequest{}
+	importOptions := postImportOptions{locales: options, shortcodeDir: *shortcodeDir}
+	switch {
+	case !*uploadLocalImages:
+	case r.DryRun:
+		importOptions.resolveImages = planImageUploads(client, *noMediaCache, &requests)
+	default:
+		importOptions.resolveImages = uploadImages(client, *noMediaCache)
 	}
 
-	enDoc, err := importer.ParseMarkdownFile(*enPath)
+	payload, tags, err := preparePostImport(ctx, client, documents, importOptions)
 	if err != nil {
-		output.PrintError("failed to parse English markdown", "invalid_markdown", err.Error(), r.Human)
+		return r.handleImportError(err)
+	}
+	slug := getString(payload, "slug")
 
-		return ExitValidation
+	if r.DryRun {
+		notes := []string{}
+		if len(requests) > 0 {
+			notes = append(notes, "image URLs are assigned when the images are uploaded")
+		}
+
+		newTags := false
+		tagValues := make([]any, 0, len(tags))
+		for _, tag := range tags {
+			if tag.ID != 0 {
+				tagValues = append(tagValues, tag.ID)
+
+				continue
+			}
+
+			requests = append(requests, plannedRequest{Method: http.MethodPost, Endpoint: endpointFor("tag", ""), Payload: newTagPayload(tag.Slug)})
+			tagValues = append(tagValues, "<id of new tag "+tag.Slug+">")
+			newTags = true
+		}
+		payload["tags"] = tagValues
+
+		request := plannedRequest{Method: http.MethodPost, Endpoint: endpointFor("post", ""), Payload: payload}
+		if *upsert {
+			request, err = planUpsert(ctx, client.Posts, "post", slug, payload)
+			if err != nil {
+				return r.handleError(err)
+			}
+		}
+		if newTags {
+			notes = append(notes, "tag ids are assigned when the tags are created")
+		}
+		request.Note = strings.Join(notes, "; ")
+
+		return r.printDryRun(append(requests, request)...)
 	}
 
-	categoryID, err := resolveCategoryID(client, viDoc.FrontMatter.CategorySlug)
+	// New tags are only created once everything else has been resolved and
+	// the payload is known to be valid.
+	tagIDs, err := createMissingTags(ctx, client, tags)
 	if err != nil {
 		return r.handleError(err)
 	}
+	payload["tags"] = tagIDs
 
-	tagIDs, err := resolveTagIDs(client, viDoc.FrontMatter.Tags)
+	var response *geda.Response
+	if *upsert {
+		_, response, err = client.Posts.Upsert(ctx, slug, payload)
+	} else {
+		_, response, err = client.Posts.Create(ctx, payload)
+	}
 	if err != nil {
 		return r.handleError(err)
 	}
 
-	payload, err := importer.BuildBilingualPostPayload(viDoc, enDoc, categoryID, tagIDs)
-	if err != nil {
-		output.PrintError("failed to build post payload", "invalid_import_payload", err.Error(), r.Human)
+	if err := output.Print(response.Body, r.outputOptions("post")); err != nil {
+		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
 
-		return ExitValidation
+		return ExitNetwork
 	}
 
-	slug := getString(payload, "slug")
-	if slug == "" {
-		output.PrintError("slug is required in markdown front matter", "missing_slug", nil, r.Human)
+	return ExitSuccess
+}
 
-		return ExitValidation
+// importError is a problem with the Markdown files of a post import, as
+// opposed to an API error.
+type importError struct {
+	message string
+	code    string
+	err     error
+}
+
+func (e *importError) Error() string {
+	if e.err == nil {
+		return e.message
 	}
 
-	if *upsert {
-		endpoint := fmt.Sprintf("/api/v1/posts/%s", slug)
-		_, err = client.Get(endpoint)
-		if err == nil {
-			response, updateErr := client.Put(endpoint, payload)
-			if updateErr != nil {
-				return r.handleError(updateErr)
-			}
+	return e.message + ": " + e.err.Error()
+}
 
-			if err := output.Print(response, r.Human); err != nil {
-				output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+func (e *importError) Unwrap() error {
+	return e.err
+}
 
-				return ExitNetwork
-			}
+// localeFiles maps a locale to the Markdown file of that edition.
+type localeFiles map[string]string
 
-			return ExitSuccess
+func (files localeFiles) setter(locale string) func(string) error {
+	return func(filePath string) error {
+		if !importer.ValidLocale(locale) {
+			return fmt.Errorf("invalid locale %q", locale)
+		}
+		if existing, ok := files[locale]; ok {
+			return fmt.Errorf("locale %s is given twice (%s and %s)", locale, existing, filePath)
 		}
 
-		apiErr := &httpclient.APIError{}
-		if !errors.As(err, &apiErr) || apiErr.Status != 404 {
-			return r.handleError(err)
+		files[locale] = filePath
+
+		return nil
+	}
+}
+
+// readLocaleFiles parses one Markdown file per locale.
+func readLocaleFiles(files localeFiles) (map[string]importer.Document, error) {
+	documents := make(map[string]importer.Document, len(files))
+	for locale, filePath := range files {
+		document, err := importer.ParseMarkdownFile(filePath)
+		if err != nil {
+			return nil, &importError{message: "failed to parse " + locale + " markdown", code: "invalid_markdown", err: err}
 		}
+
+		documents[locale] = document
 	}
 
-	response, err := client.Post("/api/v1/posts", payload)
+	return documents, nil
+}
+
+// postImportOptions are the settings of a post import besides its
+// documents.
+type postImportOptions struct {
+	locales importer.LocaleOptions
+	// resolveImages gets the images given by a relative path. They are left
+	// as they are when it is nil.
+	resolveImages imageResolver
+	// shortcodeDir holds custom shortcode templates.
+	shortcodeDir string
+}
+
+// preparePostImport builds a post from its documents, one per locale, and
+// resolves its category, existing tags and shortcode data without creating
+// anything. The payload's tags are left for the caller to fill in once
+// missing tags have been created. Images are only resolved once the payload
+// is known to be valid.
+func preparePostImport(ctx context.Context, client *geda.Client, documents map[string]importer.Document, options postImportOptions) (map[string]any, []tagRef, error) {
+	for _, locale := range slices.Concat(options.locales.Required, []string{options.locales.Fallback}) {
+		if _, ok := documents[locale]; locale != "" && !ok {
+			return nil, nil, &importError{message: "missing markdown for locale " + locale, code: "missing_locale_file"}
+		}
+	}
+
+	var images []importer.Image
+	if options.resolveImages != nil {
+		var err error
+		images, err = importer.LocalImages(documents)
+		if err != nil {
+			return nil, nil, &importError{message: "failed to read local image", code: "invalid_image", err: err}
+		}
+	}
+
+	// Category and tags come from the fallback locale, like every other
+	// shared field.
+	primary := documents[importer.OrderLocales(documents, options.locales)[0]]
+
+	categoryID, err := resolveCategoryID(ctx, client, primary.FrontMatter.CategorySlug)
 	if err != nil {
-		return r.handleError(err)
+		return nil, nil, err
 	}
 
-	if err := output.Print(response, r.Human); err != nil {
-		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+	tags, err := resolveTags(ctx, client, primary.FrontMatter.Tags)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		return ExitNetwork
+	shortcodes, err := postShortcodes(ctx, client, primary.Path, options.shortcodeDir)
+	if err != nil {
+		return nil, nil, &importError{message: "failed to load shortcodes", code: "invalid_shortcode", err: err}
 	}
 
-	return ExitSuccess
+	documents, err = importer.ExpandShortcodes(documents, shortcodes)
+	if err != nil {
+		if apiErr := (&geda.APIError{}); errors.As(err, &apiErr) {
+			return nil, nil, err
+		}
+
+		return nil, nil, &importError{message: "failed to expand shortcodes", code: "invalid_shortcode", err: err}
+	}
+
+	payload, err := importer.BuildPostPayload(documents, options.locales, categoryID, nil)
+	if err != nil {
+		return nil, nil, &importError{message: "failed to build post payload", code: "invalid_import_payload", err: err}
+	}
+
+	if getString(payload, "slug") == "" {
+		return nil, nil, &importError{message: "slug is required in markdown front matter", code: "missing_slug"}
+	}
+
+	if len(images) == 0 {
+		return payload, tags, nil
+	}
+
+	urls, err := options.resolveImages(ctx, images)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	documents, err = importer.ReplaceImages(documents, urls)
+	if err != nil {
+		return nil, nil, &importError{message: "failed to render markdown", code: "invalid_markdown", err: err}
+	}
+
+	payload, err = importer.BuildPostPayload(documents, options.locales, categoryID, nil)
+	if err != nil {
+		return nil, nil, &importError{message: "failed to build post payload", code: "invalid_import_payload", err: err}
+	}
+
+	return payload, tags, nil
 }
 
-func (r Runner) runPostUploadImage(args []string) int {
+func (r Runner) handleImportError(err error) int {
+	importErr := &importError{}
+	if !errors.As(err, &importErr) {
+		return r.handleError(err)
+	}
+
+	var details any
+	if importErr.err != nil {
+		details = importErr.err.Error()
+	}
+	output.PrintError(importErr.message, importErr.code, details, r.Human)
+
+	return ExitValidation
+}
+
+func (r Runner) runPostUploadImage(ctx context.Context, args []string) int {
 	client, err := r.authenticatedClient()
 	if err != nil {
 		output.PrintError(err.Error(), "not_logged_in", nil, r.Human)
@@ -593,20 +1036,34 @@ func (r Runner) runPostUploadImage(args []string) int {
 		return ExitValidation
 	}
 
-	fields := map[string]string{}
-	if trimmed := strings.TrimSpace(*altVI); trimmed != "" {
-		fields["alt_text[vi]"] = trimmed
+	altText := geda.Localized{
+		"vi": strings.TrimSpace(*altVI),
+		"en": strings.TrimSpace(*altEN),
 	}
-	if trimmed := strings.TrimSpace(*altEN); trimmed != "" {
-		fields["alt_text[en]"] = trimmed
+
+	if r.DryRun {
+		if _, err := os.Stat(*filePath); err != nil {
+			output.PrintError("failed to read image file", "invalid_file", err.Error(), r.Human)
+
+			return ExitValidation
+		}
+
+		fields := map[string]any{"file": "@" + *filePath}
+		for locale, text := range altText {
+			if text != "" {
+				fields["alt_text["+locale+"]"] = text
+			}
+		}
+
+		return r.printDryRun(plannedRequest{Method: http.MethodPost, Endpoint: "/api/v1/media", Payload: fields})
 	}
 
-	response, err := client.PostMultipartFile("/api/v1/media", "file", *filePath, fields)
+	_, response, err := client.Media.Upload(ctx, *filePath, altText)
 	if err != nil {
 		return r.handleError(err)
 	}
 
-	if err := output.Print(response, r.Human); err != nil {
+	if err := output.Print(response.Body, r.outputOptions("")); err != nil {
 		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
 
 		return ExitNetwork
@@ -615,7 +1072,7 @@ func (r Runner) runPostUploadImage(args []string) int {
 	return ExitSuccess
 }
 
-func (r Runner) runSettings(args []string) int {
+func (r Runner) runSettings(ctx context.Context, args []string) int {
 	if len(args) == 0 {
 		r.printSettingsUsage()
 
@@ -631,12 +1088,12 @@ func (r Runner) runSettings(args []string) int {
 
 	switch args[0] {
 	case "list":
-		response, err := client.Get("/api/v1/settings")
+		_, response, err := client.Settings.List(ctx)
 		if err != nil {
 			return r.handleError(err)
 		}
 
-		if err := output.Print(response, r.Human); err != nil {
+		if err := output.Print(response.Body, r.outputOptions("settings")); err != nil {
 			output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
 
 			return ExitNetwork
@@ -657,12 +1114,12 @@ func (r Runner) runSettings(args []string) int {
 			return ExitValidation
 		}
 
-		response, err := client.Get("/api/v1/settings/" + *key)
+		_, response, err := client.Settings.Get(ctx, *key)
 		if err != nil {
 			return r.handleError(err)
 		}
 
-		if err := output.Print(response, r.Human); err != nil {
+		if err := output.Print(response.Body, r.outputOptions("settings")); err != nil {
 			output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
 
 			return ExitNetwork
@@ -686,12 +1143,16 @@ func (r Runner) runSettings(args []string) int {
 
 		parsedValue := parseStringToValue(*value)
 
-		response, err := client.Put("/api/v1/settings/"+*key, map[string]any{"value": parsedValue})
+		if r.DryRun {
+			return r.printDryRun(plannedRequest{Method: http.MethodPut, Endpoint: "/api/v1/settings/" + *key, Payload: map[string]any{"value": parsedValue}})
+		}
+
+		_, response, err := client.Settings.Set(ctx, *key, parsedValue)
 		if err != nil {
 			return r.handleError(err)
 		}
 
-		if err := output.Print(response, r.Human); err != nil {
+		if err := output.Print(response.Body, r.outputOptions("settings")); err != nil {
 			output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
 
 			return ExitNetwork
@@ -705,37 +1166,90 @@ func (r Runner) runSettings(args []string) int {
 	}
 }
 
-func (r Runner) authenticatedClient() (*httpclient.Client, error) {
-	profile, err := config.Load()
+func (r Runner) authenticatedClient() (*geda.Client, error) {
+	settings, err := r.resolveSettings()
 	if err != nil {
 		return nil, err
 	}
 
-	if profile == nil || profile.BaseURL == "" || profile.AccessToken == "" {
+	token, err := settings.Token()
+	if err != nil {
+		return nil, err
+	}
+	if settings.BaseURL.Value == "" || token == "" {
 		return nil, errors.New("missing CLI profile, run `geda auth login`")
 	}
 
-	return httpclient.New(profile.BaseURL, profile.AccessToken), nil
+	return r.newClient(settings.BaseURL.Value, token), nil
+}
+
+func (r Runner) newClient(baseURL string, accessToken string) *geda.Client {
+	policy := geda.DefaultRetryPolicy()
+	policy.MaxAttempts = r.Retries + 1
+	policy.MaxWait = r.RetryMaxWait
+	policy.RetryPost = r.RetryPost
+
+	options := []geda.Option{
+		geda.WithRetryPolicy(policy),
+		geda.WithTimeouts(geda.Timeouts{
+			Request: r.Timeout,
+			Connect: r.ConnectTimeout,
+			Upload:  r.UploadTimeout,
+		}),
+	}
+	if r.Verbose {
+		options = append(options, geda.WithLogger(os.Stderr))
+	}
+	if r.PrintCurl {
+		options = append(options, geda.WithCurl(os.Stderr))
+	}
+	if r.Cassette != nil {
+		options = append(options, geda.WithCassette(r.Cassette))
+	}
+
+	return geda.NewClient(baseURL, accessToken, options...)
+}
+
+func (r Runner) resolveSettings() (*config.Settings, error) {
+	return config.Resolve(config.Overrides{
+		Profile: r.Profile,
+		BaseURL: r.BaseURL,
+		Token:   r.Token,
+	})
 }
 
 func (r Runner) handleError(err error) int {
+	if errors.Is(err, context.Canceled) {
+		output.PrintError("operation canceled", "canceled", nil, r.Human)
+
+		return ExitCanceled
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		output.PrintError("request timed out", "timeout", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+
 	apiErr := &httpclient.APIError{}
 	if errors.As(err, &apiErr) {
-		code := "api_error"
-		if codeValue, ok := apiErr.Body["error_code"].(string); ok {
-			code = codeValue
+		details := map[string]any{"status": apiErr.Status}
+		for key, value := range apiErr.Body {
+			if key != "message" && key != "error_code" && key != "errors" {
+				details[key] = value
+			}
+		}
+		if apiErr.RetryAfter > 0 {
+			details["retry_after"] = apiErr.RetryAfter.Seconds()
 		}
 
-		output.PrintError(apiErr.Error(), code, apiErr.Body, r.Human)
-
-		switch {
-		case apiErr.Status == 401 || apiErr.Status == 403:
-			return ExitAuth
-		case apiErr.Status >= 500:
-			return ExitNetwork
-		default:
-			return ExitValidation
+		if len(apiErr.Fields) > 0 {
+			output.PrintFieldErrors(apiErr.Error(), apiErr.Code, apiErr.Fields, details, r.Human)
+		} else {
+			output.PrintError(apiErr.Error(), apiErr.Code, details, r.Human)
 		}
+
+		return apiExitCode(apiErr)
 	}
 
 	output.PrintError(err.Error(), "request_failed", nil, r.Human)
@@ -743,40 +1257,198 @@ func (r Runner) handleError(err error) int {
 	return ExitNetwork
 }
 
-func extractHumanFlag(args []string) (bool, []string) {
+func apiExitCode(err *httpclient.APIError) int {
+	switch {
+	case errors.Is(err, httpclient.ErrUnauthorized), errors.Is(err, httpclient.ErrForbidden):
+		return ExitAuth
+	case errors.Is(err, httpclient.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, httpclient.ErrConflict):
+		return ExitConflict
+	case errors.Is(err, httpclient.ErrValidation):
+		return ExitUnprocessable
+	case errors.Is(err, httpclient.ErrRateLimited):
+		return ExitRateLimited
+	case err.Status >= 500:
+		return ExitNetwork
+	default:
+		return ExitValidation
+	}
+}
+
+func extractGlobalFlags(args []string) (Runner, []string, error) {
+	defaultPolicy := httpclient.DefaultRetryPolicy()
+	defaultTimeouts := httpclient.DefaultTimeouts()
+	runner := Runner{
+		Retries:        defaultPolicy.MaxAttempts - 1,
+		RetryMaxWait:   defaultPolicy.MaxWait,
+		Timeout:        defaultTimeouts.Request,
+		ConnectTimeout: defaultTimeouts.Connect,
+		UploadTimeout:  defaultTimeouts.Upload,
+		Verbose:        debugEnabled(os.Getenv(envDebug)),
+		Output:         output.FormatJSON,
+		Locale:         "vi",
+	}
+
+	boolFlags := map[string]*bool{
+		"--human":      &runner.Human,
+		"--verbose":    &runner.Verbose,
+		"--retry-post": &runner.RetryPost,
+		"--print-curl": &runner.PrintCurl,
+		"--raw":        &runner.Raw,
+		"--dry-run":    &runner.DryRun,
+	}
+	valueFlags := map[string]func(string) error{
+		"--profile":         stringFlag(&runner.Profile),
+		"--base-url":        stringFlag(&runner.BaseURL),
+		"--token":           stringFlag(&runner.Token),
+		"--retries":         intFlag(&runner.Retries),
+		"--retry-max-wait":  durationFlag(&runner.RetryMaxWait),
+		"--timeout":         durationFlag(&runner.Timeout),
+		"--connect-timeout": durationFlag(&runner.ConnectTimeout),
+		"--upload-timeout":  durationFlag(&runner.UploadTimeout),
+		"--output":          formatFlag(&runner.Output),
+		"--columns":         listFlag(&runner.Columns),
+		"--locale":          stringFlag(&runner.Locale),
+		"--template":        stringFlag(&runner.Template),
+		"--query":           queryFlag(&runner.Query),
+	}
+
 	filtered := make([]string, 0, len(args))
-	human := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if target, ok := boolFlags[arg]; ok {
+			*target = true
+
+			continue
+		}
 
-	for _, arg := range args {
-		if arg == "--human" {
-			human = true
+		name, value, hasValue := strings.Cut(arg, "=")
+		setValue, ok := valueFlags[name]
+		if !ok {
+			filtered = append(filtered, arg)
 
 			continue
 		}
 
-		filtered = append(filtered, arg)
+		if !hasValue {
+			if i+1 >= len(args) {
+				return runner, nil, fmt.Errorf("flag needs an argument: %s", name)
+			}
+
+			i++
+			value = args[i]
+		}
+
+		if err := setValue(value); err != nil {
+			return runner, nil, fmt.Errorf("invalid value %q for flag %s: %w", value, name, err)
+		}
 	}
 
-	return human, filtered
+	// --template on its own selects the template format.
+	if runner.Template != "" && runner.Output == output.FormatJSON {
+		runner.Output = output.FormatTemplate
+	}
+	if runner.Output == output.FormatTemplate {
+		if _, err := output.ParseTemplate(runner.Template, runner.Locale); err != nil {
+			return runner, nil, fmt.Errorf("invalid value for flag --template: %w", err)
+		}
+	}
+
+	return runner, filtered, nil
 }
 
-func extractUserEmail(response map[string]any) string {
-	user, ok := response["user"].(map[string]any)
-	if !ok {
-		return ""
+// debugEnabled treats any GEDA_DEBUG value other than empty, 0 or false as
+// a request for verbose output.
+func debugEnabled(value string) bool {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false
+	}
+
+	enabled, err := strconv.ParseBool(value)
+
+	return err != nil || enabled
+}
+
+func stringFlag(target *string) func(string) error {
+	return func(value string) error {
+		*target = value
+
+		return nil
+	}
+}
+
+func formatFlag(target *output.Format) func(string) error {
+	return func(value string) error {
+		format, err := output.ParseFormat(value)
+		if err != nil {
+			return err
+		}
+
+		*target = format
+
+		return nil
+	}
+}
+
+func queryFlag(target *string) func(string) error {
+	return func(value string) error {
+		if _, err := output.ParseQuery(value); err != nil {
+			return err
+		}
+
+		*target = value
+
+		return nil
 	}
+}
 
-	email, _ := user["email"].(string)
+// listFlag splits a comma-separated value, ignoring empty entries.
+func listFlag(target *[]string) func(string) error {
+	return func(value string) error {
+		*target = nil
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*target = append(*target, item)
+			}
+		}
 
-	return email
+		return nil
+	}
 }
 
-func emptyToNil(value string) any {
-	if strings.TrimSpace(value) == "" {
+func intFlag(target *int) func(string) error {
+	return func(value string) error {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		if parsed < 0 {
+			return errors.New("must not be negative")
+		}
+
+		*target = parsed
+
 		return nil
 	}
+}
 
-	return value
+func durationFlag(target *time.Duration) func(string) error {
+	return func(value string) error {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		if parsed < 0 {
+			return errors.New("must not be negative")
+		}
+
+		*target = parsed
+
+		return nil
+	}
 }
 
 func readJSONFile(filePath string) (map[string]any, error) {
@@ -824,84 +1496,81 @@ func resourcePlural(resource string) string {
 	}
 }
 
-func resolveCategoryID(client *httpclient.Client, slug string) (int, error) {
-	response, err := client.Get("/api/v1/categories/" + slug)
+func resolveCategoryID(ctx context.Context, client *geda.Client, slug string) (int, error) {
+	category, _, err := client.Categories.Get(ctx, slug)
 	if err != nil {
 		return 0, err
 	}
+	if category == nil || category.ID == 0 {
+		return 0, errors.New("response missing category id")
+	}
+
+	return int(category.ID), nil
+}
 
-	return extractID(response)
+// tagRef is a tag named in front matter. ID is zero when the tag does not
+// exist yet.
+type tagRef struct {
+	Slug string
+	ID   int
 }
 
-func resolveTagIDs(client *httpclient.Client, slugs []string) ([]int, error) {
-	ids := make([]int, 0, len(slugs))
+// resolveTags looks tags up by slug without creating any.
+func resolveTags(ctx context.Context, client *geda.Client, slugs []string) ([]tagRef, error) {
+	tags := make([]tagRef, 0, len(slugs))
 
 	for _, slug := range slugs {
 		if strings.TrimSpace(slug) == "" {
 			continue
 		}
 
-		response, err := client.Get("/api/v1/tags/" + slug)
-		if err != nil {
-			apiErr := &httpclient.APIError{}
-			if errors.As(err, &apiErr) && apiErr.Status == 404 {
-				createResponse, createErr := client.Post("/api/v1/tags", map[string]any{
-					"slug": slug,
-					"name": humanizeSlug(slug),
-				})
-				if createErr != nil {
-					return nil, createErr
-				}
-
-				id, extractErr := extractID(createResponse)
-				if extractErr != nil {
-					return nil, extractErr
-				}
-
-				ids = append(ids, id)
-
-				continue
-			}
+		tag, _, err := client.Tags.Get(ctx, slug)
+		if errors.Is(err, geda.ErrNotFound) {
+			tags = append(tags, tagRef{Slug: slug})
 
+			continue
+		}
+		if err != nil {
 			return nil, err
 		}
-
-		id, extractErr := extractID(response)
-		if extractErr != nil {
-			return nil, extractErr
+		if tag == nil || tag.ID == 0 {
+			return nil, errors.New("response missing tag id")
 		}
 
-		ids = append(ids, id)
+		tags = append(tags, tagRef{Slug: slug, ID: int(tag.ID)})
 	}
 
-	return ids, nil
+	return tags, nil
 }
 
-func extractID(response map[string]any) (int, error) {
-	data, ok := response["data"].(map[string]any)
-	if !ok {
-		return 0, errors.New("response missing data object")
-	}
+// createMissingTags creates the tags resolveTags did not find and returns
+// the ids of all tags in order.
+func createMissingTags(ctx context.Context, client *geda.Client, tags []tagRef) ([]int, error) {
+	ids := make([]int, 0, len(tags))
 
-	idValue, ok := data["id"]
-	if !ok {
-		return 0, errors.New("response missing id field")
-	}
+	for _, ref := range tags {
+		if ref.ID == 0 {
+			tag, _, err := client.Tags.Create(ctx, newTagPayload(ref.Slug))
+			if err != nil {
+				return nil, err
+			}
+			if tag == nil || tag.ID == 0 {
+				return nil, errors.New("response missing tag id")
+			}
 
-	switch typed := idValue.(type) {
-	case float64:
-		return int(typed), nil
-	case int:
-		return typed, nil
-	case string:
-		parsed, err := strconv.Atoi(typed)
-		if err != nil {
-			return 0, err
+			ref.ID = int(tag.ID)
 		}
 
-		return parsed, nil
-	default:
-		return 0, errors.New("unsupported id type")
+		ids = append(ids, ref.ID)
+	}
+
+	return ids, nil
+}
+
+func newTagPayload(slug string) map[string]any {
+	return map[string]any{
+		"slug": slug,
+		"name": humanizeSlug(slug),
 	}
 }
 
@@ -932,22 +1601,24 @@ func parseStringToValue(input string) any {
 	return trimmed
 }
 
-func urlEncode(value string) string {
-	replacer := strings.NewReplacer(
-		" ", "%20",
-		"+", "%2B",
-		"#", "%23",
-		"&", "%26",
-		"=", "%3D",
-		"?", "%3F",
-	)
-
-	return replacer.Replace(value)
+// outputOptions returns how to print a response about resource, which may
+// be empty for responses that are not about a resource.
+func (r Runner) outputOptions(resource string) output.Options {
+	return output.Options{
+		Human:    r.Human,
+		Format:   r.Output,
+		Columns:  r.Columns,
+		Locale:   r.Locale,
+		Template: r.Template,
+		Query:    r.Query,
+		Raw:      r.Raw,
+		Resource: resource,
+	}
 }
 
 func (r Runner) printUsage() {
-	output.PrintError("Usage: geda [--human] <command> <subcommand> [options]", "usage", map[string]any{
-		"commands": []string{"auth", "health", "post", "category", "tag", "page", "product", "settings"},
+	output.PrintError("Usage: geda [--human] [--verbose] [--print-curl] [--profile=<name>] [--base-url=<url>] [--token=<token>] [--dry-run] [--retries=<n>] [--retry-max-wait=<duration>] [--retry-post] [--timeout=<duration>] [--connect-timeout=<duration>] [--upload-timeout=<duration>] [--output=json|table|yaml|csv|tsv|template] [--columns=<fields>] [--locale=<vi|en>] [--template=<text>] [--query=<path>] [--raw] <command> <subcommand> [options]", "usage", map[string]any{
+		"commands": []string{"auth", "health", "profile", "config", "post", "category", "tag", "page", "product", "settings", "export", "status", "pull", "push", "mock"},
 	}, r.Human)
 }
 
@@ -960,7 +1631,7 @@ func (r Runner) printHealthUsage() {
 }
 
 func (r Runner) printResourceUsage(resource string) {
-	output.PrintError("Usage: geda "+resource+" <list|get|upsert|delete"+resourceUsageSuffix(resource)+">", "usage", nil, r.Human)
+	output.PrintError("Usage: geda "+resource+" <list|get|upsert|apply|patch|diff|delete"+resourceUsageSuffix(resource)+">", "usage", nil, r.Human)
 }
 
 func resourceUsageSuffix(resource string) string {
@@ -1,15 +1,23 @@
 package commands
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"geda-cli/internal/config"
+	"geda-cli/internal/credentials"
+	"geda-cli/internal/mediacache"
+	"geda-cli/internal/mockserver"
+	"geda-cli/pkg/geda"
 )
 
 func TestUnknownCommandReturnsValidationExitCode(t *testing.T) {
@@ -465,6 +473,1579 @@ func TestPostUploadImageRequiresFile(t *testing.T) {
 	}
 }
 
+func TestAuthLoginWithProfileKeepsOtherProfiles(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/auth/login" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "staging-token",
+			"user": map[string]any{
+				"email": "editor@example.com",
+			},
+		})
+	}))
+	defer server.Close()
+
+	if err := config.SaveProfile("prod", config.Profile{
+		BaseURL:     "https://geda.vn",
+		AccessToken: "prod-token",
+	}); err != nil {
+		t.Fatalf("failed to save profile: %v", err)
+	}
+
+	exitCode := Run([]string{
+		"--profile", "staging",
+		"auth", "login",
+		"--base-url", server.URL,
+		"--email", "editor@example.com",
+		"--password", "password123",
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+
+	staging, err := config.LoadProfile("staging")
+	if err != nil || staging == nil || staging.AccessToken != "staging-token" {
+		t.Fatalf("expected staging profile to be saved, got %+v (%v)", staging, err)
+	}
+
+	prod, err := config.LoadProfile("prod")
+	if err != nil || prod == nil || prod.AccessToken != "prod-token" {
+		t.Fatalf("expected prod profile to be kept, got %+v (%v)", prod, err)
+	}
+
+	current, err := config.Load()
+	if err != nil || current == nil || current.AccessToken != "prod-token" {
+		t.Fatalf("expected prod to stay current, got %+v (%v)", current, err)
+	}
+}
+
+func TestAuthLogoutClearsOnlySelectedProfile(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/auth/logout" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"message": "Logged out.",
+		})
+	}))
+	defer server.Close()
+
+	for _, name := range []string{"prod", "staging"} {
+		if err := config.SaveProfile(name, config.Profile{
+			BaseURL:     server.URL,
+			AccessToken: name + "-token",
+		}); err != nil {
+			t.Fatalf("failed to save profile: %v", err)
+		}
+	}
+
+	exitCode := Run([]string{"auth", "logout", "--profile=staging"})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+
+	staging, err := config.LoadProfile("staging")
+	if err != nil || staging == nil || staging.AccessToken != "" {
+		t.Fatalf("expected staging token to be cleared, got %+v (%v)", staging, err)
+	}
+
+	prod, err := config.LoadProfile("prod")
+	if err != nil || prod == nil || prod.AccessToken != "prod-token" {
+		t.Fatalf("expected prod profile to be kept, got %+v (%v)", prod, err)
+	}
+}
+
+func TestProfileUseSwitchesAuthenticatedClient(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	var receivedToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedToken = r.Header.Get("Authorization")
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"id": 1},
+		})
+	}))
+	defer server.Close()
+
+	for _, name := range []string{"prod", "staging"} {
+		if err := config.SaveProfile(name, config.Profile{
+			BaseURL:     server.URL,
+			AccessToken: name + "-token",
+		}); err != nil {
+			t.Fatalf("failed to save profile: %v", err)
+		}
+	}
+
+	if exitCode := Run([]string{"profile", "use", "--name", "missing"}); exitCode != ExitValidation {
+		t.Fatalf("expected exit code %d, got %d", ExitValidation, exitCode)
+	}
+	if exitCode := Run([]string{"profile", "use", "--name", "staging"}); exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+
+	if exitCode := Run([]string{"auth", "whoami"}); exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+	if receivedToken != "Bearer staging-token" {
+		t.Fatalf("expected staging token, got %q", receivedToken)
+	}
+
+	if exitCode := Run([]string{"--profile", "prod", "auth", "whoami"}); exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+	if receivedToken != "Bearer prod-token" {
+		t.Fatalf("expected prod token, got %q", receivedToken)
+	}
+}
+
+func TestEnvironmentOverridesWithoutConfigFile(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	var receivedToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedToken = r.Header.Get("Authorization")
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"id": 1},
+		})
+	}))
+	defer server.Close()
+
+	t.Setenv("GEDA_BASE_URL", server.URL)
+	t.Setenv("GEDA_TOKEN", "env-token")
+
+	if exitCode := Run([]string{"auth", "whoami"}); exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+	if receivedToken != "Bearer env-token" {
+		t.Fatalf("expected env token, got %q", receivedToken)
+	}
+
+	if exitCode := Run([]string{"auth", "whoami", "--token", "flag-token"}); exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+	if receivedToken != "Bearer flag-token" {
+		t.Fatalf("expected flag token, got %q", receivedToken)
+	}
+
+	if _, err := os.Stat(filepath.Join(homeDir, ".config", "geda-cli", "config.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected no config file to be written, got %v", err)
+	}
+}
+
+func TestConfigResolveReturnsSuccess(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	exitCode := Run([]string{"config", "resolve", "--token", "secret-token-value"})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+}
+
+func TestAuthLoginStoresTokenInCredentialStore(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	store := credentials.NewMemoryStore()
+	credentials.Register("memory", func(credentials.Options) (credentials.Store, error) {
+		return store, nil
+	})
+	t.Cleanup(func() { credentials.Unregister("memory") })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "stored-token",
+		})
+	}))
+	defer server.Close()
+
+	exitCode := Run([]string{
+		"auth", "login",
+		"--base-url", server.URL,
+		"--email", "admin@example.com",
+		"--password", "password123",
+		"--credential-store", "memory",
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+
+	token, err := store.Get(config.DefaultProfileName)
+	if err != nil || token != "stored-token" {
+		t.Fatalf("expected token in credential store, got %q (%v)", token, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(homeDir, ".config", "geda-cli", "config.json"))
+	if err != nil {
+		t.Fatalf("failed to read config file: %v", err)
+	}
+	if strings.Contains(string(data), "stored-token") {
+		t.Fatal("expected token to be kept out of config.json")
+	}
+
+	exitCode = Run([]string{"auth", "login", "--base-url", server.URL, "--email", "a", "--password", "b", "--credential-store", "bogus"})
+	if exitCode != ExitValidation {
+		t.Fatalf("expected exit code %d, got %d", ExitValidation, exitCode)
+	}
+}
+
+func TestCanceledContextReturnsCanceledExitCode(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{}})
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	runner := Runner{BaseURL: server.URL, Token: "token"}
+	exitCode := runner.Run(ctx, []string{"auth", "whoami"})
+	if exitCode != ExitCanceled {
+		t.Fatalf("expected exit code %d, got %d", ExitCanceled, exitCode)
+	}
+}
+
+func TestCassetteReplayRunsOffline(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	cassettePath := filepath.Join(t.TempDir(), "posts.yaml")
+	cassette := `interactions:
+  - request:
+      method: GET
+      url: /api/v1/posts/hello
+    response:
+      status: 200
+      headers:
+        Content-Type: application/json
+      body: '{"data":{"slug":"hello"}}'
+`
+	if err := os.WriteFile(cassettePath, []byte(cassette), 0o600); err != nil {
+		t.Fatalf("failed to write cassette: %v", err)
+	}
+
+	t.Setenv("GEDA_CASSETTE", cassettePath)
+	t.Setenv("GEDA_CASSETTE_MODE", "replay")
+	t.Setenv("GEDA_BASE_URL", "http://geda.invalid")
+	t.Setenv("GEDA_TOKEN", "token")
+
+	if exitCode := Run([]string{"post", "get", "--slug", "hello"}); exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+	if exitCode := Run([]string{"post", "get", "--slug", "other"}); exitCode != ExitNetwork {
+		t.Fatalf("expected exit code %d, got %d", ExitNetwork, exitCode)
+	}
+}
+
+func TestContentWorkflowAgainstMockServer(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	if err := mock.Seed("categories", []map[string]any{{"slug": "tin-tuc"}}); err != nil {
+		t.Fatalf("failed to seed categories: %v", err)
+	}
+
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	steps := [][]string{
+		{"auth", "login", "--base-url", server.URL, "--email", "admin@example.com", "--password", "password"},
+		{"post", "upsert", "--file", writePayloadFile(t, map[string]any{"slug": "mock-post", "status": "draft"})},
+		{"post", "upsert", "--file", writePayloadFile(t, map[string]any{"slug": "mock-post", "status": "published"})},
+		{"post", "get", "--slug", "mock-post"},
+		{"post", "list", "--status", "published"},
+		{"settings", "set", "--key", "site_name", "--value", `"GEDA"`},
+		{"settings", "get", "--key", "site_name"},
+		{"post", "delete", "--slug", "mock-post"},
+		{"auth", "logout"},
+	}
+
+	for _, args := range steps {
+		if exitCode := Run(args); exitCode != ExitSuccess {
+			t.Fatalf("expected %v to succeed, got exit code %d", args, exitCode)
+		}
+	}
+
+	if exitCode := Run([]string{"--base-url", server.URL, "--token", "bogus", "post", "get", "--slug", "mock-post"}); exitCode != ExitAuth {
+		t.Fatalf("expected exit code %d, got %d", ExitAuth, exitCode)
+	}
+}
+
+func TestResourceListAllStreamsNDJSON(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	records := make([]map[string]any, 0, 7)
+	for i := 1; i <= 7; i++ {
+		records = append(records, map[string]any{"slug": fmt.Sprintf("tag-%d", i)})
+	}
+	if err := mock.Seed("tags", records); err != nil {
+		t.Fatalf("failed to seed tags: %v", err)
+	}
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	global := []string{"--base-url", server.URL, "--token", "token"}
+
+	var exitCode int
+	stdout := captureStdout(t, func() {
+		exitCode = Run(append(global, "tag", "list", "--all", "--per-page", "3", "--ndjson"))
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("expected 7 NDJSON lines, got %d: %q", len(lines), stdout)
+	}
+	var last map[string]any
+	if err := json.Unmarshal([]byte(lines[6]), &last); err != nil || last["slug"] != "tag-7" {
+		t.Fatalf("expected last line to be tag-7, got %q (%v)", lines[6], err)
+	}
+
+	stdout = captureStdout(t, func() {
+		exitCode = Run(append(global, "tag", "list", "--all", "--per-page", "2", "--limit", "5"))
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+
+	var limited map[string]any
+	if err := json.Unmarshal([]byte(stdout), &limited); err != nil {
+		t.Fatalf("failed to decode output %q: %v", stdout, err)
+	}
+	if data := limited["data"].([]any); len(data) != 5 {
+		t.Fatalf("expected 5 items, got %d", len(data))
+	}
+
+	stdout = captureStdout(t, func() {
+		exitCode = Run(append(global, "tag", "list", "--page", "3", "--per-page", "3"))
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+
+	var single map[string]any
+	if err := json.Unmarshal([]byte(stdout), &single); err != nil {
+		t.Fatalf("failed to decode output %q: %v", stdout, err)
+	}
+	if meta := single["meta"].(map[string]any); meta["current_page"] != float64(3) || len(single["data"].([]any)) != 1 {
+		t.Fatalf("expected the single item on page 3, got %#v", single)
+	}
+
+	stdout = captureStdout(t, func() {
+		exitCode = Run(append(global, "tag", "list", "--page", "2", "--per-page", "3", "--limit", "2"))
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+
+	var pageLimited map[string]any
+	if err := json.Unmarshal([]byte(stdout), &pageLimited); err != nil {
+		t.Fatalf("failed to decode output %q: %v", stdout, err)
+	}
+	data := pageLimited["data"].([]any)
+	if len(data) != 2 || data[0].(map[string]any)["slug"] != "tag-4" || data[1].(map[string]any)["slug"] != "tag-5" {
+		t.Fatalf("expected the first 2 items of page 2, got %#v", data)
+	}
+}
+
+func TestResourceListPrintsTable(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+	t.Setenv("COLUMNS", "")
+
+	mock := mockserver.New()
+	if err := mock.Seed("posts", []map[string]any{
+		{"slug": "hello", "title": map[string]any{"vi": "Xin chào", "en": "Hello"}, "status": "published"},
+	}); err != nil {
+		t.Fatalf("failed to seed posts: %v", err)
+	}
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	var exitCode int
+	stdout := captureStdout(t, func() {
+		exitCode = Run([]string{"--base-url", server.URL, "--token", "token", "--output", "table", "--locale", "en", "post", "list"})
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "SLUG") || !strings.Contains(lines[1], "Hello") {
+		t.Fatalf("unexpected table output %q", stdout)
+	}
+
+	if exitCode := Run([]string{"--output", "xml", "post", "list"}); exitCode != ExitValidation {
+		t.Fatalf("expected exit code %d, got %d", ExitValidation, exitCode)
+	}
+}
+
+func TestOutputFormatFlags(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	if err := mock.Seed("posts", []map[string]any{
+		{"slug": "hello", "title": map[string]any{"vi": "Xin chào", "en": "Hello"}, "status": "published"},
+	}); err != nil {
+		t.Fatalf("failed to seed posts: %v", err)
+	}
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	var exitCode int
+	stdout := captureStdout(t, func() {
+		exitCode = Run([]string{"--base-url", server.URL, "--token", "token", "--output", "csv", "--columns", "slug,status", "post", "list"})
+	})
+	if exitCode != ExitSuccess || stdout != "slug,status\nhello,published\n" {
+		t.Fatalf("expected CSV output, got %d %q", exitCode, stdout)
+	}
+
+	stdout = captureStdout(t, func() {
+		exitCode = Run([]string{"--base-url", server.URL, "--token", "token", "--template", "{{.slug}}={{.title.vi}}", "post", "get", "--slug", "hello"})
+	})
+	if exitCode != ExitSuccess || stdout != "hello=Xin chào\n" {
+		t.Fatalf("expected template output, got %d %q", exitCode, stdout)
+	}
+
+	if exitCode := Run([]string{"--output", "template", "post", "list"}); exitCode != ExitValidation {
+		t.Fatalf("expected missing template to fail, got %d", exitCode)
+	}
+}
+
+func TestQueryAndRawSelectResponseFields(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	imagePath := filepath.Join(t.TempDir(), "cover.png")
+	if err := os.WriteFile(imagePath, []byte("image"), 0o600); err != nil {
+		t.Fatalf("failed to write image: %v", err)
+	}
+
+	var exitCode int
+	stdout := captureStdout(t, func() {
+		exitCode = Run([]string{"--base-url", server.URL, "--token", "token", "post", "upload-image", "--file", imagePath, "--query", "data.url", "--raw"})
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+	if !strings.HasPrefix(stdout, server.URL+"/storage/") || strings.Count(stdout, "\n") != 1 || strings.Contains(stdout, `"`) {
+		t.Fatalf("expected bare media URL, got %q", stdout)
+	}
+
+	if exitCode := Run([]string{"--query", "data[", "health", "check"}); exitCode != ExitValidation {
+		t.Fatalf("expected invalid query to fail, got %d", exitCode)
+	}
+}
+
+func TestAPIErrorsMapToExitCodes(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v1/posts/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Post not found."}`))
+		case "/api/v1/posts/busy":
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"message":"Too Many Attempts."}`))
+		case "/api/v1/posts/locked":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"message":"Post is being edited."}`))
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message":"The title.vi field is required.","errors":{"title.vi":["The title.vi field is required."],"category_id":["The selected category id is invalid."]}}`))
+		}
+	}))
+	defer server.Close()
+
+	global := []string{"--base-url", server.URL, "--token", "token", "--retries", "0"}
+
+	tests := []struct {
+		slug     string
+		exitCode int
+	}{
+		{"missing", ExitNotFound},
+		{"busy", ExitRateLimited},
+		{"locked", ExitConflict},
+		{"invalid", ExitUnprocessable},
+	}
+	for _, test := range tests {
+		if exitCode := Run(append(global, "post", "get", "--slug", test.slug)); exitCode != test.exitCode {
+			t.Fatalf("expected %s to exit with %d, got %d", test.slug, test.exitCode, exitCode)
+		}
+	}
+
+	var exitCode int
+	stderr := captureStderr(t, func() {
+		exitCode = Run(append(global, "post", "get", "--slug", "invalid"))
+	})
+	if exitCode != ExitUnprocessable {
+		t.Fatalf("expected exit code %d, got %d", ExitUnprocessable, exitCode)
+	}
+
+	var payload struct {
+		ErrorCode string `json:"error_code"`
+		Details   struct {
+			Status int                 `json:"status"`
+			Fields map[string][]string `json:"fields"`
+		} `json:"details"`
+	}
+	if err := json.Unmarshal([]byte(stderr), &payload); err != nil {
+		t.Fatalf("failed to decode error %q: %v", stderr, err)
+	}
+	if payload.ErrorCode != "validation_failed" || payload.Details.Status != 422 || len(payload.Details.Fields["category_id"]) != 1 {
+		t.Fatalf("unexpected error payload %q", stderr)
+	}
+
+	stderr = captureStderr(t, func() {
+		Run(append(global, "--human", "post", "get", "--slug", "invalid"))
+	})
+	expected := "The title.vi field is required. (validation_failed)\n  category_id: The selected category id is invalid.\n  title.vi: The title.vi field is required.\n"
+	if stderr != expected {
+		t.Fatalf("expected %q, got %q", expected, stderr)
+	}
+}
+
+func TestDryRunPlansRequestsWithoutMutating(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	if err := mock.Seed("categories", []map[string]any{{"slug": "tin-tuc"}}); err != nil {
+		t.Fatalf("failed to seed categories: %v", err)
+	}
+	if err := mock.Seed("tags", []map[string]any{{"slug": "ai"}}); err != nil {
+		t.Fatalf("failed to seed tags: %v", err)
+	}
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	global := []string{"--base-url", server.URL, "--token", "token", "--dry-run"}
+
+	var exitCode int
+	stdout := captureStdout(t, func() {
+		exitCode = Run(append(global, "post", "upsert", "--file", writePayloadFile(t, map[string]any{"slug": "dry-post"})))
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+
+	var plan struct {
+		DryRun   bool `json:"dry_run"`
+		Requests []struct {
+			Method   string         `json:"method"`
+			Endpoint string         `json:"endpoint"`
+			Payload  map[string]any `json:"payload"`
+		} `json:"requests"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &plan); err != nil {
+		t.Fatalf("failed to decode plan %q: %v", stdout, err)
+	}
+	if !plan.DryRun || len(plan.Requests) != 1 || plan.Requests[0].Method != http.MethodPost || plan.Requests[0].Endpoint != "/api/v1/posts" {
+		t.Fatalf("expected a single POST /api/v1/posts, got %s", stdout)
+	}
+
+	dir := t.TempDir()
+	for name, title := range map[string]string{"post.vi.md": "Xin chao", "post.en.md": "Hello"} {
+		content := "---\nslug: dry-post\ntitle: " + title + "\ncategory_slug: tin-tuc\ntags:\n  - ai\n  - new-tag\n---\nBody"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write markdown: %v", err)
+		}
+	}
+
+	stdout = captureStdout(t, func() {
+		exitCode = Run(append(global, "post", "import", "--vi", filepath.Join(dir, "post.vi.md"), "--en", filepath.Join(dir, "post.en.md")))
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+	if err := json.Unmarshal([]byte(stdout), &plan); err != nil {
+		t.Fatalf("failed to decode plan %q: %v", stdout, err)
+	}
+	if len(plan.Requests) != 2 || plan.Requests[0].Endpoint != "/api/v1/tags" || plan.Requests[0].Payload["slug"] != "new-tag" {
+		t.Fatalf("expected the missing tag to be planned first, got %s", stdout)
+	}
+	if tags, ok := plan.Requests[1].Payload["tags"].([]any); !ok || len(tags) != 2 || tags[0] != float64(1) {
+		t.Fatalf("expected the existing tag id in the post payload, got %s", stdout)
+	}
+
+	for _, args := range [][]string{{"tag", "get", "--slug", "new-tag"}, {"post", "get", "--slug", "dry-post"}} {
+		captureStdout(t, func() {
+			exitCode = Run(append([]string{"--base-url", server.URL, "--token", "token"}, args...))
+		})
+		if exitCode != ExitNotFound {
+			t.Fatalf("expected %v to be missing after dry run, got exit code %d", args, exitCode)
+		}
+	}
+}
+
+func TestResourceDiffAndConfirmedUpsert(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	if err := mock.Seed("posts", []map[string]any{{"slug": "xin-chao", "status": "draft", "title": map[string]any{"vi": "Xin chào", "en": "Hello"}}}); err != nil {
+		t.Fatalf("failed to seed posts: %v", err)
+	}
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	global := []string{"--base-url", server.URL, "--token", "token"}
+	payloadFile := writePayloadFile(t, map[string]any{"slug": "xin-chao", "status": "draft", "title": map[string]any{"vi": "Xin chào", "en": "Hello again"}})
+
+	var exitCode int
+	stdout := captureStdout(t, func() {
+		exitCode = Run(append(global, "post", "diff", "--file", payloadFile))
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+
+	var result struct {
+		Exists  bool `json:"exists"`
+		Changes []struct {
+			Path string `json:"path"`
+			Op   string `json:"op"`
+			New  any    `json:"new"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to decode diff %q: %v", stdout, err)
+	}
+	if !result.Exists || len(result.Changes) != 1 || result.Changes[0].Path != "title.en" || result.Changes[0].New != "Hello again" {
+		t.Fatalf("expected only title.en to change, got %s", stdout)
+	}
+
+	stdin := os.Stdin
+	t.Cleanup(func() { os.Stdin = stdin })
+
+	for _, answer := range []string{"n\n", "y\n"} {
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("failed to create pipe: %v", err)
+		}
+		_, _ = writer.WriteString(answer)
+		writer.Close()
+		os.Stdin = reader
+
+		var stderr string
+		stdout = captureStdout(t, func() {
+			stderr = captureStderr(t, func() {
+				exitCode = Run(append(global, "post", "upsert", "--file", payloadFile, "--confirm"))
+			})
+		})
+		reader.Close()
+
+		if !strings.Contains(stderr, "~ title.en") {
+			t.Fatalf("expected the diff on stderr, got %q", stderr)
+		}
+		if answer == "n\n" && (exitCode != ExitSuccess || !strings.Contains(stdout, "upsert aborted") || strings.Contains(stdout, "Hello again")) {
+			t.Fatalf("expected a declined upsert to abort with exit code %d, got %d and %q", ExitSuccess, exitCode, stdout)
+		}
+		if answer == "y\n" && (exitCode != ExitSuccess || !strings.Contains(stdout, "Hello again")) {
+			t.Fatalf("expected a confirmed upsert to apply, got exit code %d and %q", exitCode, stdout)
+		}
+	}
+}
+
+func TestResourcePatchFallsBackToPutAndChecksIfMatch(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	if err := mock.Seed("posts", []map[string]any{{
+		"slug":     "xin-chao",
+		"status":   "draft",
+		"og_image": "https://example.com/og.png",
+		"title":    map[string]any{"vi": "Xin chào", "en": "Hello"},
+	}}); err != nil {
+		t.Fatalf("failed to seed posts: %v", err)
+	}
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	global := []string{"--base-url", server.URL, "--token", "token"}
+
+	var exitCode int
+	captureStderr(t, func() {
+		exitCode = Run(append(global, "post", "patch", "--slug", "xin-chao", "--set", "status=published", "--if-match", "2000-01-01T00:00:00Z"))
+	})
+	if exitCode != ExitConflict {
+		t.Fatalf("expected a stale --if-match to exit %d, got %d", ExitConflict, exitCode)
+	}
+
+	stdout := captureStdout(t, func() {
+		exitCode = Run(append(global, "post", "patch", "--slug", "xin-chao", "--set", "status=published", "--set", "title.en=New title", "--unset", "og_image"))
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+
+	var response struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &response); err != nil {
+		t.Fatalf("failed to decode response %q: %v", stdout, err)
+	}
+
+	title, _ := response.Data["title"].(map[string]any)
+	if response.Data["status"] != "published" || title["en"] != "New title" || title["vi"] != "Xin chào" || response.Data["og_image"] != nil {
+		t.Fatalf("unexpected patched record: %#v", response.Data)
+	}
+}
+
+func TestResourcePatchSendsTouchedFields(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	var patched map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record := map[string]any{"id": 3, "slug": "xin-chao", "status": "draft", "title": map[string]any{"vi": "Xin chào", "en": "Hello"}}
+		if r.Method == http.MethodPatch {
+			_ = json.NewDecoder(r.Body).Decode(&patched)
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": record})
+	}))
+	defer server.Close()
+
+	captureStdout(t, func() {
+		if exitCode := Run([]string{"--base-url", server.URL, "--token", "token", "page", "patch", "--slug", "xin-chao", "--set", "title.en=Hi"}); exitCode != ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+		}
+	})
+
+	title, _ := patched["title"].(map[string]any)
+	if len(patched) != 1 || title["vi"] != "Xin chào" || title["en"] != "Hi" {
+		t.Fatalf("expected only the merged title to be patched, got %#v", patched)
+	}
+}
+
+func TestResourcePatchSendsIfMatchToServer(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	if err := mock.Seed("posts", []map[string]any{{
+		"slug":       "xin-chao",
+		"status":     "draft",
+		"updated_at": "2026-05-01T10:00:00.25Z",
+	}}); err != nil {
+		t.Fatalf("failed to seed posts: %v", err)
+	}
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	// Another writer changes the post right after the CLI reads it.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mock.ServeHTTP(w, r)
+		if r.Method == http.MethodGet {
+			update := httptest.NewRequest(http.MethodPut, r.URL.Path, strings.NewReader(`{"status":"archived"}`))
+			update.Header.Set("Authorization", "Bearer token")
+			mock.ServeHTTP(httptest.NewRecorder(), update)
+		}
+	}))
+	defer server.Close()
+
+	var exitCode int
+	captureStderr(t, func() {
+		exitCode = Run([]string{"--base-url", server.URL, "--token", "token", "post", "patch", "--slug", "xin-chao", "--set", "status=published", "--if-match", "2026-05-01T10:00:00.25Z"})
+	})
+	if exitCode != ExitConflict {
+		t.Fatalf("expected a write after a concurrent change to exit %d, got %d", ExitConflict, exitCode)
+	}
+}
+
+func TestResourcePatchComparesIfMatchAsInstants(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	record := map[string]any{"slug": "xin-chao", "status": "draft", "updated_at": "2026-05-01T10:00:00.250Z"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": record})
+	}))
+	defer server.Close()
+
+	patch := func(ifMatch string) int {
+		var exitCode int
+		captureStdout(t, func() {
+			captureStderr(t, func() {
+				exitCode = Run([]string{"--base-url", server.URL, "--token", "token", "page", "patch", "--slug", "xin-chao", "--set", "status=published", "--if-match", ifMatch})
+			})
+		})
+
+		return exitCode
+	}
+
+	if exitCode := patch("2026-05-01T12:00:00.25+02:00"); exitCode != ExitSuccess {
+		t.Fatalf("expected the same instant in another offset to match, got %d", exitCode)
+	}
+	if exitCode := patch("yesterday"); exitCode != ExitValidation {
+		t.Fatalf("expected an invalid --if-match to exit %d, got %d", ExitValidation, exitCode)
+	}
+
+	delete(record, "updated_at")
+	if exitCode := patch("2026-05-01T10:00:00.25Z"); exitCode != ExitValidation {
+		t.Fatalf("expected a record without updated_at to exit %d, got %d", ExitValidation, exitCode)
+	}
+}
+
+func TestResourcePatchFallbackWritesRelationsAsIDs(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	var put map[string]any
+	var unmodifiedSince string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record := map[string]any{
+			"id":          3,
+			"slug":        "xin-chao",
+			"status":      "draft",
+			"updated_at":  "2026-05-01T10:00:00.25Z",
+			"category_id": 7,
+			"category":    map[string]any{"id": 7, "slug": "news"},
+			"tags":        []any{map[string]any{"id": 1, "slug": "go"}, map[string]any{"id": 2, "slug": "cli"}},
+		}
+		switch r.Method {
+		case http.MethodPatch:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			_ = json.NewEncoder(w).Encode(map[string]any{"message": "Method not allowed."})
+
+			return
+		case http.MethodPut:
+			unmodifiedSince = r.Header.Get("If-Unmodified-Since")
+			_ = json.NewDecoder(r.Body).Decode(&put)
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": record})
+	}))
+	defer server.Close()
+
+	captureStdout(t, func() {
+		if exitCode := Run([]string{"--base-url", server.URL, "--token", "token", "post", "patch", "--slug", "xin-chao", "--set", "status=published", "--if-match", "2026-05-01T10:00:00.25Z"}); exitCode != ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+		}
+	})
+
+	if unmodifiedSince != "Fri, 01 May 2026 10:00:00 GMT" {
+		t.Fatalf("expected If-Unmodified-Since from --if-match, got %q", unmodifiedSince)
+	}
+	if _, ok := put["category"]; ok || put["category_id"] != float64(7) || put["status"] != "published" {
+		t.Fatalf("expected category written as category_id, got %#v", put)
+	}
+	if tags, _ := put["tags"].([]any); len(tags) != 2 || tags[0] != float64(1) || tags[1] != float64(2) {
+		t.Fatalf("expected tags written as ids, got %#v", put["tags"])
+	}
+}
+
+func TestResourceApplyReportsPerItemResults(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	if err := mock.Seed("products", []map[string]any{{"slug": "existing", "price": "10"}}); err != nil {
+		t.Fatalf("failed to seed products: %v", err)
+	}
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.json":     `{"slug":"existing","price":"12"}`,
+		"b.json":     `{"slug":"new-product","price":"5"}`,
+		"c.json":     `{"price":"7"}`,
+		"d.json":     `not json`,
+		"notes.txt":  `ignored`,
+		"e.json":     `{"slug":"another-product"}`,
+		"f.json.bak": `{"slug":"ignored"}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	global := []string{"--base-url", server.URL, "--token", "token"}
+
+	var exitCode int
+	stdout := captureStdout(t, func() {
+		exitCode = Run(append(global, "product", "apply", "--dir", dir, "--concurrency", "3", "--continue-on-error"))
+	})
+	if exitCode != ExitValidation {
+		t.Fatalf("expected exit code %d, got %d", ExitValidation, exitCode)
+	}
+
+	var report struct {
+		Data []struct {
+			Source string `json:"source"`
+			Action string `json:"action"`
+		} `json:"data"`
+		Summary map[string]int `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("failed to decode report %q: %v", stdout, err)
+	}
+
+	expected := map[string]int{"total": 5, "created": 2, "updated": 1, "failed": 2, "skipped": 0}
+	for key, count := range expected {
+		if report.Summary[key] != count {
+			t.Fatalf("expected %s=%d, got summary %v", key, count, report.Summary)
+		}
+	}
+	if filepath.Base(report.Data[0].Source) != "a.json" || report.Data[0].Action != "updated" {
+		t.Fatalf("expected items in file order, got %s", stdout)
+	}
+
+	stdin := os.Stdin
+	t.Cleanup(func() { os.Stdin = stdin })
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	_, _ = writer.WriteString("{\"slug\":\"streamed\"}\n\n{\"price\":\"1\"}\n{\"slug\":\"never-applied\"}\n")
+	writer.Close()
+	os.Stdin = reader
+	defer reader.Close()
+
+	stdout = captureStdout(t, func() {
+		exitCode = Run(append(global, "product", "apply", "--stdin", "--concurrency", "1"))
+	})
+	if exitCode != ExitValidation {
+		t.Fatalf("expected exit code %d, got %d", ExitValidation, exitCode)
+	}
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("failed to decode report %q: %v", stdout, err)
+	}
+	if report.Summary["created"] != 1 || report.Summary["failed"] != 1 || report.Summary["skipped"] != 1 || report.Data[1].Source != "stdin:3" {
+		t.Fatalf("expected the stream to stop after the first failure, got %s", stdout)
+	}
+}
+
+func TestPostImportWithExtraLocales(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	if err := mock.Seed("categories", []map[string]any{{"slug": "tin-tuc"}}); err != nil {
+		t.Fatalf("failed to seed categories: %v", err)
+	}
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	dir := t.TempDir()
+	for name, title := range map[string]string{"post.vi.md": "Xin chao", "post.en.md": "Hello", "post.ja.md": "Konnichiwa", "extra.md": "Annyeong"} {
+		content := "---\nslug: multi\ntitle: " + title + "\ncategory_slug: tin-tuc\n---\nBody"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write markdown: %v", err)
+		}
+	}
+
+	global := []string{"--base-url", server.URL, "--token", "token"}
+
+	var exitCode int
+	captureStdout(t, func() {
+		exitCode = Run(append(global, "post", "import",
+			"--locale-file", "ko="+filepath.Join(dir, "extra.md"),
+			filepath.Join(dir, "post.vi.md"), filepath.Join(dir, "post.en.md"), filepath.Join(dir, "post.ja.md")))
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+
+	post, _, err := geda.NewClient(server.URL, "token").Resource("posts").Get(context.Background(), "multi")
+	if err != nil {
+		t.Fatalf("failed to get post: %v", err)
+	}
+	title, _ := (*post)["title"].(map[string]any)
+	expected := map[string]any{"vi": "Xin chao", "en": "Hello", "ja": "Konnichiwa", "ko": "Annyeong"}
+	for locale, value := range expected {
+		if title[locale] != value {
+			t.Fatalf("expected title %v, got %v", expected, title)
+		}
+	}
+
+	captureStderr(t, func() {
+		exitCode = Run(append(global, "post", "import", filepath.Join(dir, "post.ja.md")))
+	})
+	if exitCode != ExitValidation {
+		t.Fatalf("expected missing required locales to exit with %d, got %d", ExitValidation, exitCode)
+	}
+
+	captureStdout(t, func() {
+		exitCode = Run(append(global, "post", "import", "--required-locales", "ja", filepath.Join(dir, "post.ja.md")))
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected an import with only the required locale to succeed, got %d", exitCode)
+	}
+}
+
+func TestPostImportSingleMultilingualFile(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	if err := mock.Seed("categories", []map[string]any{{"slug": "tin-tuc"}}); err != nil {
+		t.Fatalf("failed to seed categories: %v", err)
+	}
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	filePath := filepath.Join(t.TempDir(), "post.md")
+	content := "---\nslug: single\ntitle: {vi: Xin chao, en: Hello}\ncategory_slug: tin-tuc\n---\n<!-- lang:vi -->\nNoi dung\n\n<!-- lang:en -->\nContent\n"
+	if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write markdown: %v", err)
+	}
+
+	global := []string{"--base-url", server.URL, "--token", "token"}
+
+	var exitCode int
+	captureStdout(t, func() {
+		exitCode = Run(append(global, "post", "import", "--file", filePath))
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+
+	post, _, err := geda.NewClient(server.URL, "token").Posts.Get(context.Background(), "single")
+	if err != nil {
+		t.Fatalf("failed to get post: %v", err)
+	}
+	if post.Title["vi"] != "Xin chao" || post.Title["en"] != "Hello" || post.Body["en"] != "<p>Content</p>" {
+		t.Fatalf("unexpected post: %#v", post)
+	}
+
+	captureStderr(t, func() {
+		exitCode = Run(append(global, "post", "import", "--file", filePath, "--vi", filePath))
+	})
+	if exitCode != ExitValidation {
+		t.Fatalf("expected --file with per-locale files to exit with %d, got %d", ExitValidation, exitCode)
+	}
+}
+
+func TestPostImportUploadsLocalImagesOnce(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	if err := mock.Seed("categories", []map[string]any{{"slug": "tin-tuc"}}); err != nil {
+		t.Fatalf("failed to seed categories: %v", err)
+	}
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	var uploads []map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/api/v1/media" {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("failed to parse multipart form: %v", err)
+			}
+			uploads = append(uploads, r.MultipartForm.Value)
+		}
+		mock.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"img/chart.png": "\x89PNG\r\n\x1a\nchart",
+		"post.vi.md":    "---\nslug: images\ntitle: Hinh\ncategory_slug: tin-tuc\nfeatured_image: ./img/chart.png\n---\n![Bieu do](./img/chart.png)",
+		"post.en.md":    "---\nslug: images\ntitle: Images\ncategory_slug: tin-tuc\n---\n![Chart](img/chart.png)",
+	}
+	for name, content := range files {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	global := []string{"--base-url", server.URL, "--token", "token"}
+	args := []string{"post", "import", filepath.Join(dir, "post.vi.md"), filepath.Join(dir, "post.en.md")}
+
+	var exitCode int
+	stdout := captureStdout(t, func() {
+		exitCode = Run(append(global, append([]string{"--dry-run"}, args...)...))
+	})
+	if exitCode != ExitSuccess || !strings.Contains(stdout, `"endpoint":"/api/v1/media"`) || len(uploads) != 0 {
+		t.Fatalf("expected the dry run to plan the upload without sending it, got %d: %s", exitCode, stdout)
+	}
+
+	for range 2 {
+		captureStdout(t, func() {
+			exitCode = Run(append(global, args...))
+		})
+		if exitCode != ExitSuccess {
+			t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+		}
+	}
+
+	if len(uploads) != 1 {
+		t.Fatalf("expected a single upload across both imports, got %d", len(uploads))
+	}
+	if uploads[0]["alt_text[vi]"][0] != "Bieu do" || uploads[0]["alt_text[en]"][0] != "Chart" {
+		t.Fatalf("unexpected alt text: %v", uploads[0])
+	}
+
+	captureStdout(t, func() {
+		exitCode = Run(append(global, append([]string{"post", "import", "--no-media-cache"}, args[2:]...)...))
+	})
+	if exitCode != ExitSuccess || len(uploads) != 2 {
+		t.Fatalf("expected --no-media-cache to upload again, got exit code %d and %d uploads", exitCode, len(uploads))
+	}
+
+	post, _, err := geda.NewClient(server.URL, "token").Posts.Get(context.Background(), "images")
+	if err != nil {
+		t.Fatalf("failed to get post: %v", err)
+	}
+	if !strings.Contains(post.FeaturedImage, "/storage/") || !strings.Contains(post.Body["en"], `src="`+post.FeaturedImage+`"`) {
+		t.Fatalf("expected uploaded URLs in the post, got %#v", post)
+	}
+}
+
+func TestPostImportRejectsUploadWithoutMedia(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	if err := mock.Seed("categories", []map[string]any{{"slug": "tin-tuc"}}); err != nil {
+		t.Fatalf("failed to seed categories: %v", err)
+	}
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/api/v1/media" {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"message":"Uploaded"}`))
+
+			return
+		}
+		mock.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"chart.png":  "\x89PNG\r\n\x1a\nchart",
+		"post.vi.md": "---\nslug: images\ntitle: Hinh\ncategory_slug: tin-tuc\n---\n![Bieu do](chart.png)",
+		"post.en.md": "---\nslug: images\ntitle: Images\ncategory_slug: tin-tuc\n---\nNone",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	var exitCode int
+	captureStdout(t, func() {
+		exitCode = Run([]string{"--base-url", server.URL, "--token", "token", "post", "import", filepath.Join(dir, "post.vi.md"), filepath.Join(dir, "post.en.md")})
+	})
+	if exitCode == ExitSuccess {
+		t.Fatal("expected an upload without media to fail the import")
+	}
+
+	cache, err := mediacache.Load(filepath.Join(homeDir, ".config", "geda-cli", "media-cache.json"))
+	if err != nil {
+		t.Fatalf("failed to load media cache: %v", err)
+	}
+	if len(cache.Sites[server.URL]) != 0 {
+		t.Fatalf("expected nothing cached, got %v", cache.Sites)
+	}
+}
+
+func TestPostImportExpandsShortcodes(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	if err := mock.Seed("categories", []map[string]any{{"slug": "tin-tuc"}}); err != nil {
+		t.Fatalf("failed to seed categories: %v", err)
+	}
+	if err := mock.Seed("products", []map[string]any{{"slug": "lamp", "price": "10", "name": map[string]any{"vi": "Đèn", "en": "Lamp"}}}); err != nil {
+		t.Fatalf("failed to seed products: %v", err)
+	}
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/products/ghost" {
+			_, _ = w.Write([]byte(`{"message":"OK"}`))
+
+			return
+		}
+		mock.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"templates/badge.html": `<span class="badge">{{ .Localized (.Get "text") }}</span>`,
+		"post.vi.md":           "---\nslug: shortcodes\ntitle: Ma ngan\ncategory_slug: tin-tuc\n---\n{{< product slug=\"lamp\" >}}\n\n{{< badge text=\"Mới\" />}}",
+		"post.en.md":           "---\nslug: shortcodes\ntitle: Shortcodes\ncategory_slug: tin-tuc\n---\n{{< product lamp >}}\n\n{{< badge text=\"New\" />}}",
+		"missing.vi.md":        "---\nslug: missing\ntitle: Thieu\ncategory_slug: tin-tuc\n---\n{{< product slug=\"nope\" >}}",
+		"missing.en.md":        "---\nslug: missing\ntitle: Missing\ncategory_slug: tin-tuc\n---\nNone",
+		"ghost.vi.md":          "---\nslug: ghost\ntitle: Ma\ncategory_slug: tin-tuc\n---\n{{< product ghost >}}",
+		"ghost.en.md":          "---\nslug: ghost\ntitle: Ghost\ncategory_slug: tin-tuc\n---\nNone",
+	}
+	for name, content := range files {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	global := []string{"--base-url", server.URL, "--token", "token"}
+
+	var exitCode int
+	captureStdout(t, func() {
+		exitCode = Run(append(global, "post", "import", "--shortcodes", filepath.Join(dir, "templates"), filepath.Join(dir, "post.vi.md"), filepath.Join(dir, "post.en.md")))
+	})
+	if exitCode != ExitSuccess {
+		t.Fatalf("expected exit code %d, got %d", ExitSuccess, exitCode)
+	}
+
+	post, _, err := geda.NewClient(server.URL, "token").Posts.Get(context.Background(), "shortcodes")
+	if err != nil {
+		t.Fatalf("failed to get post: %v", err)
+	}
+	if !strings.Contains(post.Body["vi"], `href="/products/lamp">Đèn</a>`) || !strings.Contains(post.Body["vi"], `<span class="badge">Mới</span>`) {
+		t.Fatalf("expected expanded shortcodes in the vi body, got %s", post.Body["vi"])
+	}
+	if !strings.Contains(post.Body["en"], `href="/products/lamp">Lamp</a>`) || !strings.Contains(post.Body["en"], `<span class="badge">New</span>`) {
+		t.Fatalf("expected expanded shortcodes in the en body, got %s", post.Body["en"])
+	}
+
+	captureStdout(t, func() {
+		exitCode = Run(append(global, "post", "import", filepath.Join(dir, "missing.vi.md"), filepath.Join(dir, "missing.en.md")))
+	})
+	if exitCode != ExitNotFound {
+		t.Fatalf("expected exit code %d for a missing product, got %d", ExitNotFound, exitCode)
+	}
+
+	captureStdout(t, func() {
+		exitCode = Run(append(global, "post", "import", filepath.Join(dir, "ghost.vi.md"), filepath.Join(dir, "ghost.en.md")))
+	})
+	if exitCode != ExitValidation {
+		t.Fatalf("expected exit code %d for a product response without data, got %d", ExitValidation, exitCode)
+	}
+}
+
+func TestSyncStatusPushAndPull(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	if err := mock.Seed("products", []map[string]any{{"slug": "lamp", "price": "10"}}); err != nil {
+		t.Fatalf("failed to seed products: %v", err)
+	}
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	dir := t.TempDir()
+	global := []string{"--base-url", server.URL, "--token", "token"}
+	client := geda.NewClient(server.URL, "token")
+	productPath := filepath.Join(dir, "products", "lamp.json")
+
+	run := func(args ...string) (int, string) {
+		var exitCode int
+		stdout := captureStdout(t, func() {
+			exitCode = Run(append(global, args...))
+		})
+
+		return exitCode, stdout
+	}
+	statuses := func() map[string]string {
+		exitCode, stdout := run("status", "--dir", dir)
+		if exitCode != ExitSuccess {
+			t.Fatalf("status exited with %d: %s", exitCode, stdout)
+		}
+
+		var report struct {
+			Data []struct {
+				Key    string `json:"key"`
+				Status string `json:"status"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+			t.Fatalf("failed to decode status %q: %v", stdout, err)
+		}
+
+		result := map[string]string{}
+		for _, item := range report.Data {
+			result[item.Key] = item.Status
+		}
+
+		return result
+	}
+	editLocal := func(price string) {
+		payload, err := readJSONFile(productPath)
+		if err != nil {
+			t.Fatalf("failed to read product file: %v", err)
+		}
+		payload["price"] = price
+
+		content, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("failed to encode product: %v", err)
+		}
+		if err := os.WriteFile(productPath, content, 0o600); err != nil {
+			t.Fatalf("failed to write product file: %v", err)
+		}
+	}
+
+	if exitCode, stdout := run("export", "--dir", dir, "--media=false"); exitCode != ExitSuccess {
+		t.Fatalf("export exited with %d: %s", exitCode, stdout)
+	}
+	if changes := statuses(); len(changes) != 0 {
+		t.Fatalf("expected a fresh export to be in sync, got %v", changes)
+	}
+
+	editLocal("12")
+	if changes := statuses(); changes["products/lamp"] != "modified" {
+		t.Fatalf("expected a local edit to show as modified, got %v", changes)
+	}
+	if exitCode, stdout := run("push", "--dir", dir); exitCode != ExitSuccess || !strings.Contains(stdout, `"pushed"`) {
+		t.Fatalf("push exited with %d: %s", exitCode, stdout)
+	}
+	remote, _, err := client.Resource("products").Get(context.Background(), "lamp")
+	if err != nil || (*remote)["price"] != "12" {
+		t.Fatalf("expected push to update the remote price, got %v (%v)", remote, err)
+	}
+	if changes := statuses(); len(changes) != 0 {
+		t.Fatalf("expected push to leave the directory in sync, got %v", changes)
+	}
+
+	if _, _, err := client.Resource("products").Update(context.Background(), "lamp", map[string]any{"price": "15"}); err != nil {
+		t.Fatalf("failed to update product: %v", err)
+	}
+	if changes := statuses(); changes["products/lamp"] != "remote_modified" {
+		t.Fatalf("expected a remote edit to show as remote_modified, got %v", changes)
+	}
+	if exitCode, stdout := run("pull", "--dir", dir); exitCode != ExitSuccess {
+		t.Fatalf("pull exited with %d: %s", exitCode, stdout)
+	}
+	if payload, err := readJSONFile(productPath); err != nil || payload["price"] != "15" {
+		t.Fatalf("expected pull to write the remote price, got %v (%v)", payload, err)
+	}
+
+	editLocal("20")
+	if _, _, err := client.Resource("products").Update(context.Background(), "lamp", map[string]any{"price": "25"}); err != nil {
+		t.Fatalf("failed to update product: %v", err)
+	}
+	if changes := statuses(); changes["products/lamp"] != "conflict" {
+		t.Fatalf("expected edits on both sides to conflict, got %v", changes)
+	}
+	if exitCode, stdout := run("push", "--dir", dir); exitCode != ExitConflict || !strings.Contains(stdout, `"skipped"`) {
+		t.Fatalf("expected push to skip the conflict with exit code %d, got %d: %s", ExitConflict, exitCode, stdout)
+	}
+	if exitCode, stdout := run("pull", "--dir", dir, "--force"); exitCode != ExitSuccess {
+		t.Fatalf("forced pull exited with %d: %s", exitCode, stdout)
+	}
+	if payload, err := readJSONFile(productPath); err != nil || payload["price"] != "25" {
+		t.Fatalf("expected a forced pull to take the remote side, got %v (%v)", payload, err)
+	}
+}
+
+func TestPushSendsPulledRecordInWriteShape(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	mock := mockserver.New()
+	if err := mock.Seed("products", []map[string]any{{
+		"slug":     "lamp",
+		"price":    "10",
+		"category": map[string]any{"id": 3, "slug": "lighting"},
+		"tags":     []any{map[string]any{"id": 1, "slug": "desk"}, map[string]any{"id": 2, "slug": "led"}},
+	}}); err != nil {
+		t.Fatalf("failed to seed products: %v", err)
+	}
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	var written map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut || r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &written)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		mock.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	global := []string{"--base-url", server.URL, "--token", "token"}
+	run := func(args ...string) (int, string) {
+		var exitCode int
+		stdout := captureStdout(t, func() {
+			exitCode = Run(append(global, args...))
+		})
+
+		return exitCode, stdout
+	}
+
+	if exitCode, stdout := run("pull", "--dir", dir); exitCode != ExitSuccess {
+		t.Fatalf("pull exited with %d: %s", exitCode, stdout)
+	}
+
+	productPath := filepath.Join(dir, "products", "lamp.json")
+	payload, err := readJSONFile(productPath)
+	if err != nil {
+		t.Fatalf("failed to read pulled product: %v", err)
+	}
+	payload["price"] = "12"
+	content, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to encode product: %v", err)
+	}
+	if err := os.WriteFile(productPath, content, 0o600); err != nil {
+		t.Fatalf("failed to write product file: %v", err)
+	}
+
+	if exitCode, stdout := run("push", "--dir", dir); exitCode != ExitSuccess {
+		t.Fatalf("push exited with %d: %s", exitCode, stdout)
+	}
+
+	for _, field := range []string{"id", "created_at", "updated_at", "category"} {
+		if _, ok := written[field]; ok {
+			t.Fatalf("expected %s to be left out of the pushed payload, got %#v", field, written)
+		}
+	}
+	tags, _ := written["tags"].([]any)
+	if written["price"] != "12" || written["category_id"] != float64(3) || len(tags) != 2 || tags[0] != float64(1) {
+		t.Fatalf("expected the pulled record pushed in write shape, got %#v", written)
+	}
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	return captureFile(t, &os.Stdout, fn)
+}
+
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+
+	return captureFile(t, &os.Stderr, fn)
+}
+
+func captureFile(t *testing.T, target **os.File, fn func()) string {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+
+	original := *target
+	*target = writer
+	defer func() {
+		*target = original
+	}()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		done <- string(data)
+	}()
+
+	fn()
+
+	writer.Close()
+
+	return <-done
+}
+
 func writePayloadFile(t *testing.T, payload map[string]any) string {
 	t.Helper()
 
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+
+	"geda-cli/internal/importer"
+	"geda-cli/pkg/geda"
+)
+
+// postShortcodes loads the shortcodes for the post at markdownPath and lets
+// product look up its product through the API.
+func postShortcodes(ctx context.Context, client *geda.Client, markdownPath string, dir string) (*importer.Shortcodes, error) {
+	shortcodes, err := importer.LoadShortcodes(markdownPath, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every locale of a post usually shows the same products.
+	products := map[string]map[string]any{}
+	shortcodes.SetData("product", func(call importer.ShortcodeCall) (any, error) {
+		slug := cmp.Or(call.Get("slug"), call.Get(0))
+		if slug == "" {
+			return nil, errors.New(`missing product slug, as in {{< product slug="x" >}}`)
+		}
+
+		if product, ok := products[slug]; ok {
+			return product, nil
+		}
+
+		record, _, err := client.Resource("products").Get(ctx, slug)
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			return nil, fmt.Errorf("product %q not found", slug)
+		}
+
+		products[slug] = *record
+
+		return *record, nil
+	})
+
+	return shortcodes, nil
+}
@@ -0,0 +1,368 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"path/filepath"
+	"strings"
+
+	"geda-cli/internal/contentsync"
+	"geda-cli/internal/diff"
+	"geda-cli/internal/importer"
+	"geda-cli/internal/output"
+	"geda-cli/pkg/geda"
+)
+
+const (
+	syncPulled  = "pulled"
+	syncPushed  = "pushed"
+	syncDeleted = "deleted"
+	syncSkipped = "skipped"
+	syncFailed  = "failed"
+)
+
+// syncResult is one line of the pull or push report.
+type syncResult struct {
+	Key       string             `json:"key"`
+	Status    contentsync.Status `json:"status"`
+	Action    string             `json:"action"`
+	Error     string             `json:"error,omitempty"`
+	ErrorCode string             `json:"error_code,omitempty"`
+
+	exitCode int
+}
+
+func (result *syncResult) fail(err error) {
+	result.Action = syncFailed
+	result.Error = err.Error()
+	result.ErrorCode = "request_failed"
+	result.exitCode = ExitNetwork
+
+	apiErr := &geda.APIError{}
+	importErr := &importError{}
+	switch {
+	case errors.As(err, &apiErr):
+		result.ErrorCode = apiErr.Code
+		result.exitCode = apiExitCode(apiErr)
+	case errors.As(err, &importErr):
+		result.ErrorCode = importErr.code
+		result.exitCode = ExitValidation
+	case errors.Is(err, context.Canceled):
+		result.ErrorCode = "canceled"
+		result.exitCode = ExitCanceled
+	}
+}
+
+// syncSession is a content directory compared with the API.
+type syncSession struct {
+	client  *geda.Client
+	dir     string
+	state   *contentsync.State
+	site    *contentsync.Site
+	changes []contentsync.Change
+}
+
+func (r Runner) openSync(ctx context.Context, name string, args []string, extra func(*flag.FlagSet)) (*syncSession, int) {
+	client, err := r.authenticatedClient()
+	if err != nil {
+		output.PrintError(err.Error(), "not_logged_in", nil, r.Human)
+
+		return nil, ExitAuth
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	dir := fs.String("dir", ".", "Content directory")
+	if extra != nil {
+		extra(fs)
+	}
+	if err := fs.Parse(args); err != nil {
+		output.PrintError(err.Error(), "parse_error", nil, r.Human)
+
+		return nil, ExitValidation
+	}
+
+	state, err := contentsync.LoadState(*dir)
+	if err != nil {
+		output.PrintError("failed to read sync state", "invalid_state", err.Error(), r.Human)
+
+		return nil, ExitValidation
+	}
+
+	local, err := contentsync.ScanLocal(*dir)
+	if err != nil {
+		output.PrintError("failed to read content directory", "invalid_content_dir", err.Error(), r.Human)
+
+		return nil, ExitValidation
+	}
+
+	site, err := contentsync.FetchSite(ctx, client)
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+
+	return &syncSession{
+		client:  client,
+		dir:     *dir,
+		state:   state,
+		site:    site,
+		changes: contentsync.Compare(state, local, site),
+	}, ExitSuccess
+}
+
+// runStatus lists what pull and push would do. It saves the state because
+// comparing adopts records that are already identical on both sides.
+func (r Runner) runStatus(ctx context.Context, args []string) int {
+	session, exitCode := r.openSync(ctx, "status", args, nil)
+	if session == nil {
+		return exitCode
+	}
+	if !r.DryRun {
+		if err := session.state.Save(session.dir); err != nil {
+			output.PrintError("failed to save sync state", "invalid_state", err.Error(), r.Human)
+
+			return ExitValidation
+		}
+	}
+
+	summary := map[contentsync.Status]int{}
+	for _, status := range []contentsync.Status{
+		contentsync.StatusAdded, contentsync.StatusModified, contentsync.StatusDeleted, contentsync.StatusConflict,
+		contentsync.StatusRemoteAdded, contentsync.StatusRemoteModified, contentsync.StatusRemoteDeleted,
+	} {
+		summary[status] = 0
+	}
+	for _, change := range session.changes {
+		summary[change.Status]++
+	}
+
+	if err := output.Print(map[string]any{
+		"data":    session.changes,
+		"summary": summary,
+	}, r.outputOptions("")); err != nil {
+		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+
+	return ExitSuccess
+}
+
+// runPull brings remote changes into the content directory. Conflicts are
+// skipped unless --force, which takes the remote side.
+func (r Runner) runPull(ctx context.Context, args []string) int {
+	var force, markdown *bool
+	session, exitCode := r.openSync(ctx, "pull", args, func(fs *flag.FlagSet) {
+		force = fs.Bool("force", false, "Overwrite local changes to records that changed on both sides")
+		markdown = fs.Bool("markdown", false, "Write new posts as per-locale Markdown files")
+	})
+	if session == nil {
+		return exitCode
+	}
+
+	results := []*syncResult{}
+	for _, change := range session.changes {
+		switch change.Status {
+		case contentsync.StatusRemoteAdded, contentsync.StatusRemoteModified, contentsync.StatusRemoteDeleted, contentsync.StatusConflict:
+		default:
+			continue
+		}
+
+		result := &syncResult{Key: change.Key, Status: change.Status}
+		results = append(results, result)
+
+		switch {
+		case change.Status == contentsync.StatusConflict && !*force:
+			result.Action = syncSkipped
+		case r.DryRun:
+			result.Action = "would_pull"
+		case change.Remote == nil:
+			if err := contentsync.RemoveFiles(session.dir, change.Local.Files, nil); err != nil {
+				result.fail(err)
+
+				continue
+			}
+			delete(session.state.Items, change.Key)
+			result.Action = syncDeleted
+		default:
+			asMarkdown := *markdown && change.Resource == "posts"
+			previous := []string{}
+			if change.Local != nil {
+				asMarkdown = change.Local.Markdown
+				previous = change.Local.Files
+			}
+
+			files, err := session.site.Render(change.Remote, asMarkdown)
+			if err != nil {
+				result.fail(err)
+
+				continue
+			}
+
+			hash, err := contentsync.WriteFiles(session.dir, files, previous)
+			if err != nil {
+				result.fail(err)
+
+				continue
+			}
+
+			session.state.Items[change.Key] = contentsync.Entry{UpdatedAt: change.Remote.UpdatedAt, RemoteHash: change.Remote.Hash, Hash: hash}
+			result.Action = syncPulled
+		}
+	}
+
+	return r.finishSync(session, results)
+}
+
+// runPush sends local changes to the API. Conflicts are skipped unless
+// --force, which takes the local side.
+func (r Runner) runPush(ctx context.Context, args []string) int {
+	var force, noMediaCache *bool
+	session, exitCode := r.openSync(ctx, "push", args, func(fs *flag.FlagSet) {
+		force = fs.Bool("force", false, "Overwrite remote changes to records that changed on both sides")
+		noMediaCache = fs.Bool("no-media-cache", false, "Upload images again even when the media cache has their URLs")
+	})
+	if session == nil {
+		return exitCode
+	}
+
+	results := []*syncResult{}
+	for _, change := range session.changes {
+		switch change.Status {
+		case contentsync.StatusAdded, contentsync.StatusModified, contentsync.StatusDeleted, contentsync.StatusConflict:
+		default:
+			continue
+		}
+
+		result := &syncResult{Key: change.Key, Status: change.Status}
+		results = append(results, result)
+
+		switch {
+		case change.Status == contentsync.StatusConflict && !*force:
+			result.Action = syncSkipped
+		case r.DryRun:
+			result.Action = "would_push"
+		case change.Local == nil:
+			_, err := session.client.Resource(change.Resource).Delete(ctx, change.Slug)
+			if err != nil && !errors.Is(err, geda.ErrNotFound) {
+				result.fail(err)
+
+				continue
+			}
+			delete(session.state.Items, change.Key)
+			result.Action = syncDeleted
+		default:
+			record, err := pushLocal(ctx, session.client, session.dir, change.Local, uploadImages(session.client, *noMediaCache))
+			if err != nil {
+				result.fail(err)
+
+				continue
+			}
+
+			remote := session.site.Add(change.Resource, record)
+			session.state.Items[change.Key] = contentsync.Entry{UpdatedAt: remote.UpdatedAt, RemoteHash: remote.Hash, Hash: change.Local.Hash}
+			result.Action = syncPushed
+		}
+	}
+
+	return r.finishSync(session, results)
+}
+
+// pushLocal upserts a record from its local files. Markdown posts go
+// through the same steps as post import, with images resolving their local
+// images.
+func pushLocal(ctx context.Context, client *geda.Client, dir string, local *contentsync.Local, images imageResolver) (map[string]any, error) {
+	service := client.Resource(local.Resource)
+
+	var payload map[string]any
+	if local.Markdown {
+		files := localeFiles{}
+		for _, file := range local.Files {
+			if locale, ok := importer.LocaleFromPath(file); ok {
+				files[locale] = filepath.Join(dir, filepath.FromSlash(file))
+			}
+		}
+
+		documents, err := readLocaleFiles(files)
+		if err != nil {
+			return nil, err
+		}
+
+		var tags []tagRef
+		payload, tags, err = preparePostImport(ctx, client, documents, postImportOptions{locales: importer.DefaultLocales, resolveImages: images})
+		if err != nil {
+			return nil, err
+		}
+
+		tagIDs, err := createMissingTags(ctx, client, tags)
+		if err != nil {
+			return nil, err
+		}
+		payload["tags"] = tagIDs
+	} else {
+		var err error
+		payload, err = readJSONFile(filepath.Join(dir, filepath.FromSlash(local.Files[0])))
+		if err != nil {
+			return nil, &importError{message: "failed to read payload file", code: "invalid_payload_file", err: err}
+		}
+
+		// Pulled files hold the record as the API reads it.
+		payload = diff.WritePayload(payload)
+	}
+
+	slug := strings.TrimSpace(getString(payload, "slug"))
+	if slug == "" {
+		slug = local.Slug
+	}
+
+	record, _, err := service.Upsert(ctx, slug, payload)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return map[string]any{}, nil
+	}
+
+	return *record, nil
+}
+
+// finishSync saves the state and prints the report. The exit code is that
+// of the first failure, or ExitConflict when conflicts were skipped.
+func (r Runner) finishSync(session *syncSession, results []*syncResult) int {
+	if !r.DryRun {
+		if err := session.state.Save(session.dir); err != nil {
+			output.PrintError("failed to save sync state", "invalid_state", err.Error(), r.Human)
+
+			return ExitValidation
+		}
+	}
+
+	summary := map[string]int{}
+	exitCode := ExitSuccess
+	for _, result := range results {
+		summary[result.Action]++
+
+		switch {
+		case result.Action == syncFailed && (exitCode == ExitSuccess || exitCode == ExitConflict):
+			exitCode = result.exitCode
+		case result.Action == syncSkipped && exitCode == ExitSuccess:
+			exitCode = ExitConflict
+		}
+	}
+
+	report := map[string]any{
+		"data":    results,
+		"summary": summary,
+	}
+	if r.DryRun {
+		report["dry_run"] = true
+	}
+
+	if err := output.Print(report, r.outputOptions("")); err != nil {
+		output.PrintError("failed to print output", "print_error", err.Error(), r.Human)
+
+		return ExitNetwork
+	}
+
+	return exitCode
+}
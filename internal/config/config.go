@@ -3,9 +3,21 @@ package config
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
+
+	"geda-cli/internal/credentials"
+)
+
+const DefaultProfileName = "default"
+
+var (
+	ErrProfileNotFound = errors.New("profile not found")
+	ErrProfileExists   = errors.New("profile already exists")
 )
 
 type Profile struct {
@@ -13,6 +25,33 @@ type Profile struct {
 	AccessToken string `json:"access_token"`
 	UserEmail   string `json:"user_email"`
 	LastLoginAt string `json:"last_login_at"`
+	// CredentialStore names the backend holding AccessToken when it is not
+	// kept inline (see the credentials package); empty means plaintext.
+	CredentialStore  string `json:"credential_store,omitempty"`
+	CredentialHelper string `json:"credential_helper,omitempty"`
+}
+
+// LoggedIn reports whether the profile has a saved token, without reading it
+// from an external credential store.
+func (p Profile) LoggedIn() bool {
+	if p.AccessToken != "" {
+		return true
+	}
+
+	return credentials.IsExternal(p.CredentialStore) && p.LastLoginAt != ""
+}
+
+// File is the on-disk layout of config.json: a set of named profiles plus a
+// pointer to the one used when no profile is selected explicitly.
+type File struct {
+	CurrentProfile string             `json:"current_profile"`
+	Profiles       map[string]Profile `json:"profiles"`
+}
+
+// legacyFile matches config.json written before named profiles existed.
+type legacyFile struct {
+	File
+	Profile
 }
 
 func pathFromHome(home string) string {
@@ -28,7 +67,32 @@ func Path() (string, error) {
 	return pathFromHome(home), nil
 }
 
-func Load() (*Profile, error) {
+// ResolveName returns the profile name to use for the given selection,
+// falling back to the current profile and then to DefaultProfileName.
+func (f *File) ResolveName(name string) string {
+	if trimmed := strings.TrimSpace(name); trimmed != "" {
+		return trimmed
+	}
+
+	if f != nil && f.CurrentProfile != "" {
+		return f.CurrentProfile
+	}
+
+	return DefaultProfileName
+}
+
+// Names returns the profile names in sorted order.
+func (f *File) Names() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func LoadFile() (*File, error) {
 	path, err := Path()
 	if err != nil {
 		return nil, err
@@ -37,21 +101,31 @@ func Load() (*Profile, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return nil, nil
+			return &File{Profiles: map[string]Profile{}}, nil
 		}
 
 		return nil, err
 	}
 
-	var profile Profile
-	if err := json.Unmarshal(data, &profile); err != nil {
+	var raw legacyFile
+	if err := json.Unmarshal(data, &raw); err != nil {
 		return nil, err
 	}
 
-	return &profile, nil
+	file := raw.File
+	if file.Profiles == nil {
+		file.Profiles = map[string]Profile{}
+	}
+
+	if len(file.Profiles) == 0 && (raw.Profile.BaseURL != "" || raw.Profile.AccessToken != "") {
+		file.Profiles[DefaultProfileName] = raw.Profile
+		file.CurrentProfile = DefaultProfileName
+	}
+
+	return &file, nil
 }
 
-func Save(profile Profile) error {
+func SaveFile(file *File) error {
 	path, err := Path()
 	if err != nil {
 		return err
@@ -62,18 +136,270 @@ func Save(profile Profile) error {
 		return err
 	}
 
+	if file.Profiles == nil {
+		file.Profiles = map[string]Profile{}
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Load returns the current profile, or nil when nothing has been saved.
+func Load() (*Profile, error) {
+	return LoadProfile("")
+}
+
+// LoadProfile returns the named profile (the current one when name is
+// empty), or nil when it does not exist.
+func LoadProfile(name string) (*Profile, error) {
+	file, err := LoadFile()
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := file.ResolveName(name)
+	profile, ok := file.Profiles[resolved]
+	if !ok {
+		return nil, nil
+	}
+
+	token, err := loadToken(resolved, profile)
+	if err != nil {
+		return nil, err
+	}
+	profile.AccessToken = token
+
+	return &profile, nil
+}
+
+// Save writes the current profile.
+func Save(profile Profile) error {
+	return SaveProfile("", profile)
+}
+
+// SaveProfile writes the named profile (the current one when name is empty).
+// The first profile saved becomes the current profile.
+func SaveProfile(name string, profile Profile) error {
+	file, err := LoadFile()
+	if err != nil {
+		return err
+	}
+
+	resolved := file.ResolveName(name)
+	if err := ValidateProfileName(resolved); err != nil {
+		return err
+	}
+
 	if profile.LastLoginAt == "" {
 		profile.LastLoginAt = time.Now().UTC().Format(time.RFC3339)
 	}
 
-	data, err := json.MarshalIndent(profile, "", "  ")
+	// Moving to another store leaves no stale copy behind; this is best
+	// effort so an unreachable old backend cannot block a new login.
+	if previous, ok := file.Profiles[resolved]; ok &&
+		(previous.CredentialStore != profile.CredentialStore || previous.CredentialHelper != profile.CredentialHelper) {
+		_ = deleteToken(resolved, previous)
+	}
+
+	if credentials.IsExternal(profile.CredentialStore) {
+		store, err := openStore(profile)
+		if err != nil {
+			return err
+		}
+
+		if err := store.Set(resolved, profile.AccessToken); err != nil {
+			return fmt.Errorf("failed to store token in %s: %w", profile.CredentialStore, err)
+		}
+
+		profile.AccessToken = ""
+	}
+
+	file.Profiles[resolved] = profile
+	if file.CurrentProfile == "" {
+		file.CurrentProfile = resolved
+	}
+
+	return SaveFile(file)
+}
+
+// ClearProfile removes the login of the named profile but keeps its base URL,
+// leaving every other profile untouched.
+func ClearProfile(name string) error {
+	file, err := LoadFile()
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(path, data, 0o600)
+	resolved := file.ResolveName(name)
+	profile, ok := file.Profiles[resolved]
+	if !ok {
+		return nil
+	}
+
+	if err := deleteToken(resolved, profile); err != nil {
+		return err
+	}
+
+	file.Profiles[resolved] = Profile{
+		BaseURL:          profile.BaseURL,
+		CredentialStore:  profile.CredentialStore,
+		CredentialHelper: profile.CredentialHelper,
+	}
+
+	return SaveFile(file)
+}
+
+func UseProfile(name string) error {
+	file, err := LoadFile()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := file.Profiles[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	file.CurrentProfile = name
+
+	return SaveFile(file)
+}
+
+func RemoveProfile(name string) error {
+	file, err := LoadFile()
+	if err != nil {
+		return err
+	}
+
+	profile, ok := file.Profiles[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	if err := deleteToken(name, profile); err != nil {
+		return err
+	}
+
+	delete(file.Profiles, name)
+	if file.CurrentProfile == name {
+		file.CurrentProfile = ""
+	}
+
+	return SaveFile(file)
+}
+
+func RenameProfile(oldName string, newName string) error {
+	if err := ValidateProfileName(newName); err != nil {
+		return err
+	}
+
+	file, err := LoadFile()
+	if err != nil {
+		return err
+	}
+
+	profile, ok := file.Profiles[oldName]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, oldName)
+	}
+	if _, exists := file.Profiles[newName]; exists {
+		return fmt.Errorf("%w: %s", ErrProfileExists, newName)
+	}
+
+	if credentials.IsExternal(profile.CredentialStore) {
+		store, err := openStore(profile)
+		if err != nil {
+			return err
+		}
+
+		token, err := store.Get(oldName)
+		if err != nil && !errors.Is(err, credentials.ErrNotFound) {
+			return err
+		}
+		if token != "" {
+			if err := store.Set(newName, token); err != nil {
+				return err
+			}
+		}
+		if err := store.Delete(oldName); err != nil {
+			return err
+		}
+	}
+
+	delete(file.Profiles, oldName)
+	file.Profiles[newName] = profile
+	if file.CurrentProfile == oldName {
+		file.CurrentProfile = newName
+	}
+
+	return SaveFile(file)
+}
+
+func ValidateProfileName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("profile name is required")
+	}
+
+	if strings.ContainsAny(name, " \t\r\n/\\") {
+		return fmt.Errorf("invalid profile name %q", name)
+	}
+
+	return nil
+}
+
+func openStore(profile Profile) (credentials.Store, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.Open(profile.CredentialStore, credentials.Options{
+		Dir:        filepath.Dir(path),
+		Helper:     profile.CredentialHelper,
+		Passphrase: os.Getenv(EnvPassphrase),
+	})
+}
+
+// loadToken returns the token of the named profile, reading it from the
+// profile's credential store when it is not kept inline.
+func loadToken(name string, profile Profile) (string, error) {
+	if !credentials.IsExternal(profile.CredentialStore) {
+		return profile.AccessToken, nil
+	}
+
+	store, err := openStore(profile)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := store.Get(name)
+	if errors.Is(err, credentials.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read token from %s: %w", profile.CredentialStore, err)
+	}
+
+	return token, nil
+}
+
+func deleteToken(name string, profile Profile) error {
+	if !credentials.IsExternal(profile.CredentialStore) {
+		return nil
+	}
+
+	store, err := openStore(profile)
+	if err != nil {
+		return err
+	}
+
+	return store.Delete(name)
 }
 
+// Clear removes the whole config file, including every profile.
 func Clear() error {
 	path, err := Path()
 	if err != nil {
@@ -1,9 +1,13 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"geda-cli/internal/credentials"
 )
 
 func TestSaveLoadAndClearProfile(t *testing.T) {
@@ -60,3 +64,193 @@ func TestSaveLoadAndClearProfile(t *testing.T) {
 		t.Fatalf("expected profile file to be removed, got: %v", err)
 	}
 }
+
+func TestNamedProfilesAreIndependent(t *testing.T) {
+	setTempHome(t, t.TempDir())
+
+	if err := SaveProfile("prod", Profile{BaseURL: "https://geda.vn", AccessToken: "prod-token"}); err != nil {
+		t.Fatalf("failed to save prod profile: %v", err)
+	}
+	if err := SaveProfile("staging", Profile{BaseURL: "https://staging.geda.vn", AccessToken: "staging-token"}); err != nil {
+		t.Fatalf("failed to save staging profile: %v", err)
+	}
+
+	current, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load current profile: %v", err)
+	}
+	if current == nil || current.AccessToken != "prod-token" {
+		t.Fatalf("expected first saved profile to be current, got %+v", current)
+	}
+
+	if err := ClearProfile("staging"); err != nil {
+		t.Fatalf("failed to clear staging profile: %v", err)
+	}
+
+	staging, err := LoadProfile("staging")
+	if err != nil {
+		t.Fatalf("failed to load staging profile: %v", err)
+	}
+	if staging == nil || staging.AccessToken != "" || staging.BaseURL != "https://staging.geda.vn" {
+		t.Fatalf("expected staging token cleared and base url kept, got %+v", staging)
+	}
+
+	prod, err := LoadProfile("prod")
+	if err != nil {
+		t.Fatalf("failed to load prod profile: %v", err)
+	}
+	if prod == nil || prod.AccessToken != "prod-token" {
+		t.Fatalf("expected prod profile untouched, got %+v", prod)
+	}
+}
+
+func TestLoadFileMigratesLegacyProfile(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	configPath := pathFromHome(homeDir)
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o700); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	legacy := `{"base_url":"http://localhost:8000","access_token":"legacy-token","user_email":"admin@example.com"}`
+	if err := os.WriteFile(configPath, []byte(legacy), 0o600); err != nil {
+		t.Fatalf("failed to write legacy config: %v", err)
+	}
+
+	file, err := LoadFile()
+	if err != nil {
+		t.Fatalf("failed to load legacy config: %v", err)
+	}
+	if file.CurrentProfile != DefaultProfileName {
+		t.Fatalf("expected current profile %s, got %s", DefaultProfileName, file.CurrentProfile)
+	}
+	if file.Profiles[DefaultProfileName].AccessToken != "legacy-token" {
+		t.Fatalf("expected legacy token in default profile, got %+v", file.Profiles)
+	}
+}
+
+func TestRenameAndRemoveProfile(t *testing.T) {
+	setTempHome(t, t.TempDir())
+
+	if err := SaveProfile("local", Profile{BaseURL: "http://localhost:8000"}); err != nil {
+		t.Fatalf("failed to save local profile: %v", err)
+	}
+	if err := SaveProfile("prod", Profile{BaseURL: "https://geda.vn"}); err != nil {
+		t.Fatalf("failed to save prod profile: %v", err)
+	}
+
+	if err := RenameProfile("local", "prod"); !errors.Is(err, ErrProfileExists) {
+		t.Fatalf("expected ErrProfileExists, got %v", err)
+	}
+	if err := RenameProfile("local", "dev"); err != nil {
+		t.Fatalf("failed to rename profile: %v", err)
+	}
+
+	file, err := LoadFile()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if file.CurrentProfile != "dev" {
+		t.Fatalf("expected current profile to follow rename, got %s", file.CurrentProfile)
+	}
+
+	if err := RemoveProfile("dev"); err != nil {
+		t.Fatalf("failed to remove profile: %v", err)
+	}
+	if err := RemoveProfile("dev"); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("expected ErrProfileNotFound, got %v", err)
+	}
+
+	if err := UseProfile("prod"); err != nil {
+		t.Fatalf("failed to switch profile: %v", err)
+	}
+
+	current, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load current profile: %v", err)
+	}
+	if current == nil || current.BaseURL != "https://geda.vn" {
+		t.Fatalf("expected prod to be current, got %+v", current)
+	}
+}
+
+func setTempHome(t *testing.T, homeDir string) {
+	t.Helper()
+
+	previousHome := os.Getenv("HOME")
+	t.Cleanup(func() {
+		_ = os.Setenv("HOME", previousHome)
+	})
+
+	if err := os.Setenv("HOME", homeDir); err != nil {
+		t.Fatalf("failed to set HOME: %v", err)
+	}
+}
+
+func TestSaveProfileWithExternalCredentialStore(t *testing.T) {
+	homeDir := t.TempDir()
+	setTempHome(t, homeDir)
+
+	store := credentials.NewMemoryStore()
+	credentials.Register("memory", func(credentials.Options) (credentials.Store, error) {
+		return store, nil
+	})
+	t.Cleanup(func() { credentials.Unregister("memory") })
+
+	if err := SaveProfile("prod", Profile{
+		BaseURL:         "https://geda.vn",
+		AccessToken:     "secret-token",
+		CredentialStore: "memory",
+	}); err != nil {
+		t.Fatalf("failed to save profile: %v", err)
+	}
+
+	data, err := os.ReadFile(pathFromHome(homeDir))
+	if err != nil {
+		t.Fatalf("failed to read config file: %v", err)
+	}
+	if strings.Contains(string(data), "secret-token") {
+		t.Fatal("expected token to be kept out of config.json")
+	}
+
+	loaded, err := LoadProfile("prod")
+	if err != nil {
+		t.Fatalf("failed to load profile: %v", err)
+	}
+	if loaded == nil || loaded.AccessToken != "secret-token" || !loaded.LoggedIn() {
+		t.Fatalf("expected token from credential store, got %+v", loaded)
+	}
+
+	if err := RenameProfile("prod", "production"); err != nil {
+		t.Fatalf("failed to rename profile: %v", err)
+	}
+	if _, err := store.Get("prod"); !errors.Is(err, credentials.ErrNotFound) {
+		t.Fatalf("expected old token to be moved, got %v", err)
+	}
+
+	settings, err := Resolve(Overrides{Profile: "production"})
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	if settings.AccessToken.Value != "" || settings.AccessToken.Source != SourceCredentialStore {
+		t.Fatalf("expected the token to be left in the store, got %+v", settings.AccessToken)
+	}
+	if token, err := settings.Token(); err != nil || token != "secret-token" {
+		t.Fatalf("expected token from credential store, got %q (%v)", token, err)
+	}
+
+	if err := ClearProfile("production"); err != nil {
+		t.Fatalf("failed to clear profile: %v", err)
+	}
+	if _, err := store.Get("production"); !errors.Is(err, credentials.ErrNotFound) {
+		t.Fatalf("expected token to be deleted from store, got %v", err)
+	}
+
+	cleared, err := LoadProfile("production")
+	if err != nil {
+		t.Fatalf("failed to load profile: %v", err)
+	}
+	if cleared == nil || cleared.LoggedIn() || cleared.CredentialStore != "memory" {
+		t.Fatalf("expected logged out profile keeping its store, got %+v", cleared)
+	}
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"strings"
+
+	"geda-cli/internal/credentials"
+)
+
+const (
+	EnvProfile = "GEDA_PROFILE"
+	EnvBaseURL = "GEDA_BASE_URL"
+	EnvToken   = "GEDA_TOKEN"
+	// EnvCredentialStore selects the credential store used by `auth login`
+	// when --credential-store is not given.
+	EnvCredentialStore = "GEDA_CREDENTIAL_STORE"
+	EnvPassphrase      = "GEDA_CREDENTIALS_PASSPHRASE"
+)
+
+const (
+	SourceFlag    = "flag"
+	SourceEnv     = "env"
+	SourceProfile = "profile"
+	SourceConfig  = "config"
+	SourceDefault = "default"
+	SourceUnset   = "unset"
+	// SourceCredentialStore marks a token kept in the profile's credential
+	// store. Its value is only read by Settings.Token.
+	SourceCredentialStore = "credential_store"
+)
+
+// Overrides carries the values given on the command line; empty fields are
+// treated as not set.
+type Overrides struct {
+	Profile string
+	BaseURL string
+	Token   string
+}
+
+type Setting struct {
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// Settings is the effective configuration after applying, in order of
+// precedence: command-line flags, GEDA_* environment variables, and the
+// selected profile from config.json.
+type Settings struct {
+	Profile     Setting
+	BaseURL     Setting
+	AccessToken Setting
+	UserEmail   string
+	// Saved is the stored profile, or nil when the selected profile does not
+	// exist on disk.
+	Saved *Profile
+}
+
+func Resolve(overrides Overrides) (*Settings, error) {
+	file, err := LoadFile()
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &Settings{}
+
+	switch {
+	case strings.TrimSpace(overrides.Profile) != "":
+		settings.Profile = Setting{Value: strings.TrimSpace(overrides.Profile), Source: SourceFlag}
+	case strings.TrimSpace(os.Getenv(EnvProfile)) != "":
+		settings.Profile = Setting{Value: strings.TrimSpace(os.Getenv(EnvProfile)), Source: SourceEnv}
+	case file.CurrentProfile != "":
+		settings.Profile = Setting{Value: file.CurrentProfile, Source: SourceConfig}
+	default:
+		settings.Profile = Setting{Value: DefaultProfileName, Source: SourceDefault}
+	}
+
+	profile, ok := file.Profiles[settings.Profile.Value]
+	if ok {
+		settings.Saved = &profile
+		settings.UserEmail = profile.UserEmail
+	}
+
+	settings.BaseURL = resolveSetting(overrides.BaseURL, EnvBaseURL, profile.BaseURL)
+	settings.BaseURL.Value = strings.TrimRight(settings.BaseURL.Value, "/")
+	settings.AccessToken = resolveSetting(overrides.Token, EnvToken, profile.AccessToken)
+	if settings.AccessToken.Source == SourceUnset && ok && credentials.IsExternal(profile.CredentialStore) {
+		settings.AccessToken = Setting{Source: SourceCredentialStore}
+	}
+
+	return settings, nil
+}
+
+// Token returns the access token, reading it from the credential store of
+// the profile when it is kept there. Resolve leaves the store closed, since
+// opening one may need a passphrase that commands without a token, such as
+// auth login, do not have.
+func (s *Settings) Token() (string, error) {
+	if s.AccessToken.Source != SourceCredentialStore || s.Saved == nil {
+		return s.AccessToken.Value, nil
+	}
+
+	return loadToken(s.Profile.Value, *s.Saved)
+}
+
+func resolveSetting(flagValue string, envName string, profileValue string) Setting {
+	if trimmed := strings.TrimSpace(flagValue); trimmed != "" {
+		return Setting{Value: trimmed, Source: SourceFlag}
+	}
+
+	if trimmed := strings.TrimSpace(os.Getenv(envName)); trimmed != "" {
+		return Setting{Value: trimmed, Source: SourceEnv}
+	}
+
+	if profileValue != "" {
+		return Setting{Value: profileValue, Source: SourceProfile}
+	}
+
+	return Setting{Source: SourceUnset}
+}
+
+// RedactToken masks a token for display, keeping only the last four
+// characters of long tokens.
+func RedactToken(token string) string {
+	if token == "" {
+		return ""
+	}
+
+	if len(token) <= 8 {
+		return "****"
+	}
+
+	return "****" + token[len(token)-4:]
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"testing"
+
+	"geda-cli/internal/credentials"
+)
+
+func TestResolvePrecedence(t *testing.T) {
+	setTempHome(t, t.TempDir())
+	t.Setenv(EnvProfile, "")
+	t.Setenv(EnvBaseURL, "")
+	t.Setenv(EnvToken, "")
+
+	if err := SaveProfile("prod", Profile{BaseURL: "https://geda.vn/", AccessToken: "prod-token"}); err != nil {
+		t.Fatalf("failed to save prod profile: %v", err)
+	}
+	if err := SaveProfile("staging", Profile{BaseURL: "https://staging.geda.vn", AccessToken: "staging-token"}); err != nil {
+		t.Fatalf("failed to save staging profile: %v", err)
+	}
+
+	settings, err := Resolve(Overrides{})
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	if settings.Profile.Value != "prod" || settings.Profile.Source != SourceConfig {
+		t.Fatalf("unexpected profile setting: %+v", settings.Profile)
+	}
+	if settings.BaseURL.Value != "https://geda.vn" || settings.BaseURL.Source != SourceProfile {
+		t.Fatalf("unexpected base url setting: %+v", settings.BaseURL)
+	}
+
+	t.Setenv(EnvProfile, "staging")
+	t.Setenv(EnvToken, "env-token")
+
+	settings, err = Resolve(Overrides{})
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	if settings.Profile.Value != "staging" || settings.Profile.Source != SourceEnv {
+		t.Fatalf("unexpected profile setting: %+v", settings.Profile)
+	}
+	if settings.BaseURL.Value != "https://staging.geda.vn" {
+		t.Fatalf("expected staging base url, got %+v", settings.BaseURL)
+	}
+	if settings.AccessToken.Value != "env-token" || settings.AccessToken.Source != SourceEnv {
+		t.Fatalf("unexpected token setting: %+v", settings.AccessToken)
+	}
+
+	settings, err = Resolve(Overrides{Profile: "prod", Token: "flag-token", BaseURL: "http://localhost:8000"})
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	if settings.Profile.Value != "prod" || settings.Profile.Source != SourceFlag {
+		t.Fatalf("unexpected profile setting: %+v", settings.Profile)
+	}
+	if settings.AccessToken.Value != "flag-token" || settings.AccessToken.Source != SourceFlag {
+		t.Fatalf("unexpected token setting: %+v", settings.AccessToken)
+	}
+	if settings.BaseURL.Value != "http://localhost:8000" || settings.BaseURL.Source != SourceFlag {
+		t.Fatalf("unexpected base url setting: %+v", settings.BaseURL)
+	}
+}
+
+func TestResolveWithoutConfigFile(t *testing.T) {
+	setTempHome(t, t.TempDir())
+	t.Setenv(EnvProfile, "")
+	t.Setenv(EnvBaseURL, "")
+	t.Setenv(EnvToken, "")
+
+	settings, err := Resolve(Overrides{})
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	if settings.Profile.Value != DefaultProfileName || settings.Profile.Source != SourceDefault {
+		t.Fatalf("unexpected profile setting: %+v", settings.Profile)
+	}
+	if settings.Saved != nil {
+		t.Fatalf("expected no saved profile, got %+v", settings.Saved)
+	}
+	if settings.AccessToken.Source != SourceUnset {
+		t.Fatalf("expected token to be unset, got %+v", settings.AccessToken)
+	}
+}
+
+func TestRedactToken(t *testing.T) {
+	if got := RedactToken("1|abcdefghijklmnop"); got != "****mnop" {
+		t.Fatalf("unexpected redacted token: %s", got)
+	}
+	if got := RedactToken("short"); got != "****" {
+		t.Fatalf("unexpected redacted short token: %s", got)
+	}
+}
+
+func TestResolveLeavesLockedCredentialStoreClosed(t *testing.T) {
+	setTempHome(t, t.TempDir())
+	t.Setenv(EnvProfile, "")
+	t.Setenv(EnvBaseURL, "")
+	t.Setenv(EnvToken, "")
+	t.Setenv(EnvPassphrase, "passphrase")
+
+	if err := SaveProfile("prod", Profile{BaseURL: "https://geda.vn", AccessToken: "secret-token", CredentialStore: credentials.EncryptedFile}); err != nil {
+		t.Fatalf("failed to save profile: %v", err)
+	}
+
+	t.Setenv(EnvPassphrase, "")
+	settings, err := Resolve(Overrides{})
+	if err != nil {
+		t.Fatalf("expected resolve to work without the passphrase: %v", err)
+	}
+	if settings.BaseURL.Value != "https://geda.vn" || settings.AccessToken.Source != SourceCredentialStore {
+		t.Fatalf("unexpected settings: %+v", settings)
+	}
+	if _, err := settings.Token(); err == nil {
+		t.Fatal("expected reading the token to need the passphrase")
+	}
+
+	t.Setenv(EnvPassphrase, "passphrase")
+	if token, err := settings.Token(); err != nil || token != "secret-token" {
+		t.Fatalf("expected the stored token, got %q (%v)", token, err)
+	}
+}
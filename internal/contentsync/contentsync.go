@@ -0,0 +1,379 @@
+package contentsync
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"geda-cli/internal/exporter"
+	"geda-cli/internal/importer"
+	"geda-cli/pkg/geda"
+)
+
+const StateFile = ".geda-state.json"
+
+const stateVersion = 1
+
+type State struct {
+	Version int              `json:"version"`
+	Items   map[string]Entry `json:"items"`
+}
+
+type Entry struct {
+	UpdatedAt string `json:"updated_at,omitempty"`
+	// RemoteHash stands in for UpdatedAt when the API does not send it.
+	RemoteHash string `json:"remote_hash"`
+	Hash       string `json:"hash"`
+}
+
+func LoadState(dir string) (*State, error) {
+	state := &State{Version: stateVersion, Items: map[string]Entry{}}
+
+	content, err := os.ReadFile(filepath.Join(dir, StateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, state); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", StateFile, err)
+	}
+	if state.Items == nil {
+		state.Items = map[string]Entry{}
+	}
+
+	return state, nil
+}
+
+func (s *State) Save(dir string) error {
+	encoded, err := exporter.EncodeJSON(s)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, StateFile), encoded, 0o644)
+}
+
+// A post with <slug>.<locale>.md files is Markdown, and its JSON file, if
+// any, is ignored.
+type Local struct {
+	Key      string
+	Resource string
+	Slug     string
+	Files    []string
+	Markdown bool
+	Hash     string
+}
+
+func ScanLocal(dir string) (map[string]*Local, error) {
+	items := map[string]*Local{}
+
+	for _, resource := range exporter.Resources {
+		entries, err := os.ReadDir(filepath.Join(dir, resource))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		jsonFiles := map[string]string{}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+
+			name := entry.Name()
+			if slug, ok := strings.CutSuffix(name, ".json"); ok {
+				jsonFiles[slug] = path.Join(resource, name)
+
+				continue
+			}
+
+			if resource != "posts" {
+				continue
+			}
+			if locale, ok := importer.LocaleFromPath(name); ok {
+				local := addLocal(items, resource, strings.TrimSuffix(name, "."+locale+".md"))
+				local.Markdown = true
+				local.Files = append(local.Files, path.Join(resource, name))
+			}
+		}
+
+		for slug, file := range jsonFiles {
+			if existing, ok := items[resource+"/"+slug]; ok && existing.Markdown {
+				continue
+			}
+
+			addLocal(items, resource, slug).Files = []string{file}
+		}
+	}
+
+	for _, local := range items {
+		sort.Strings(local.Files)
+
+		contents := map[string][]byte{}
+		for _, file := range local.Files {
+			content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+			if err != nil {
+				return nil, err
+			}
+			contents[file] = content
+		}
+		local.Hash = Hash(contents)
+	}
+
+	return items, nil
+}
+
+func addLocal(items map[string]*Local, resource string, slug string) *Local {
+	key := resource + "/" + slug
+	if items[key] == nil {
+		items[key] = &Local{Key: key, Resource: resource, Slug: slug}
+	}
+
+	return items[key]
+}
+
+type Remote struct {
+	Key       string
+	Resource  string
+	Slug      string
+	UpdatedAt string
+	Hash      string
+	Record    map[string]any
+}
+
+type Site struct {
+	Records map[string]*Remote
+	Lookups exporter.Lookups
+}
+
+func FetchSite(ctx context.Context, client *geda.Client) (*Site, error) {
+	site := &Site{Records: map[string]*Remote{}}
+	records := map[string][]map[string]any{}
+
+	for _, resource := range exporter.Resources {
+		for record, err := range client.Resource(resource).All(ctx, &geda.ListOptions{PerPage: 100}) {
+			if err != nil {
+				return nil, fmt.Errorf("failed to list %s: %w", resource, err)
+			}
+
+			site.Add(resource, record)
+			records[resource] = append(records[resource], record)
+		}
+	}
+
+	site.Lookups = exporter.NewLookups(records["categories"], records["tags"])
+
+	return site, nil
+}
+
+func (s *Site) Add(resource string, record map[string]any) *Remote {
+	slug := exporter.FileName(record)
+	remote := &Remote{
+		Key:      resource + "/" + slug,
+		Resource: resource,
+		Slug:     slug,
+		Record:   record,
+	}
+	remote.UpdatedAt, _ = record["updated_at"].(string)
+
+	encoded, err := exporter.EncodeJSON(record)
+	if err == nil {
+		remote.Hash = Hash(map[string][]byte{"": encoded})
+	}
+
+	s.Records[remote.Key] = remote
+
+	return remote
+}
+
+func (s *Site) Render(remote *Remote, markdown bool) (map[string][]byte, error) {
+	if markdown && remote.Resource == "posts" {
+		documents, err := exporter.PostMarkdown(remote.Record, s.Lookups)
+		if err != nil {
+			return nil, err
+		}
+
+		files := map[string][]byte{}
+		for locale, content := range documents {
+			files[path.Join(remote.Resource, remote.Slug+"."+locale+".md")] = content
+		}
+
+		return files, nil
+	}
+
+	encoded, err := exporter.EncodeJSON(remote.Record)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{path.Join(remote.Resource, remote.Slug+".json"): encoded}, nil
+}
+
+func Hash(files map[string][]byte) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		hash.Write([]byte(name))
+		hash.Write([]byte{0})
+		hash.Write(files[name])
+		hash.Write([]byte{0})
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+type Status string
+
+const (
+	StatusAdded          Status = "added"
+	StatusModified       Status = "modified"
+	StatusDeleted        Status = "deleted"
+	StatusRemoteAdded    Status = "remote_added"
+	StatusRemoteModified Status = "remote_modified"
+	StatusRemoteDeleted  Status = "remote_deleted"
+	StatusConflict       Status = "conflict"
+)
+
+type Change struct {
+	Key      string `json:"key"`
+	Resource string `json:"resource"`
+	Slug     string `json:"slug"`
+	Status   Status `json:"status"`
+
+	Local  *Local  `json:"-"`
+	Remote *Remote `json:"-"`
+}
+
+// A side changed when it differs from state, and a record changed on both
+// sides is a conflict, unless its local files are exactly what pull would
+// write: then it is adopted into state as in sync, so a freshly exported
+// directory needs no first pull.
+func Compare(state *State, local map[string]*Local, site *Site) []Change {
+	keys := map[string]bool{}
+	for key := range state.Items {
+		keys[key] = true
+	}
+	for key := range local {
+		keys[key] = true
+	}
+	for key := range site.Records {
+		keys[key] = true
+	}
+
+	changes := []Change{}
+	for key := range keys {
+		entry, tracked := state.Items[key]
+		localItem, remote := local[key], site.Records[key]
+
+		if localItem == nil && remote == nil {
+			delete(state.Items, key)
+
+			continue
+		}
+
+		localChanged := !tracked || localItem == nil || localItem.Hash != entry.Hash
+		remoteChanged := !tracked || remote == nil || remoteChangedSince(remote, entry)
+
+		var status Status
+		switch {
+		case !localChanged && !remoteChanged:
+			continue
+		case tracked && !remoteChanged && localItem == nil:
+			status = StatusDeleted
+		case tracked && !remoteChanged:
+			status = StatusModified
+		case tracked && !localChanged && remote == nil:
+			status = StatusRemoteDeleted
+		case tracked && !localChanged:
+			status = StatusRemoteModified
+		case !tracked && remote == nil:
+			status = StatusAdded
+		case !tracked && localItem == nil:
+			status = StatusRemoteAdded
+		case localItem != nil && remote != nil && matches(localItem, remote, site):
+			state.Items[key] = Entry{UpdatedAt: remote.UpdatedAt, RemoteHash: remote.Hash, Hash: localItem.Hash}
+
+			continue
+		default:
+			status = StatusConflict
+		}
+
+		resource, slug, _ := strings.Cut(key, "/")
+		changes = append(changes, Change{Key: key, Resource: resource, Slug: slug, Status: status, Local: localItem, Remote: remote})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+func remoteChangedSince(remote *Remote, entry Entry) bool {
+	if remote.UpdatedAt != "" || entry.UpdatedAt != "" {
+		return remote.UpdatedAt != entry.UpdatedAt
+	}
+
+	return remote.Hash != entry.RemoteHash
+}
+
+func matches(local *Local, remote *Remote, site *Site) bool {
+	files, err := site.Render(remote, local.Markdown)
+
+	return err == nil && Hash(files) == local.Hash
+}
+
+func WriteFiles(dir string, files map[string][]byte, previous []string) (string, error) {
+	for name, content := range files {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return "", err
+		}
+
+		if existing, err := os.ReadFile(target); err == nil && bytes.Equal(existing, content) {
+			continue
+		}
+		if err := os.WriteFile(target, content, 0o644); err != nil {
+			return "", err
+		}
+	}
+
+	if err := RemoveFiles(dir, previous, files); err != nil {
+		return "", err
+	}
+
+	return Hash(files), nil
+}
+
+func RemoveFiles(dir string, names []string, keep map[string][]byte) error {
+	for _, name := range names {
+		if _, ok := keep[name]; ok {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(name))); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package contentsync
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCompareClassifiesChanges(t *testing.T) {
+	site := &Site{Records: map[string]*Remote{}}
+	remote := func(slug string, updatedAt string) *Remote {
+		return site.Add("tags", map[string]any{"slug": slug, "updated_at": updatedAt})
+	}
+	local := func(slug string, hash string) *Local {
+		return &Local{Key: "tags/" + slug, Resource: "tags", Slug: slug, Files: []string{"tags/" + slug + ".json"}, Hash: hash}
+	}
+
+	remote("same", "t1")
+	remote("remote-edit", "t2")
+	remote("local-edit", "t1")
+	remote("both-edit", "t2")
+	remote("remote-new", "t1")
+	remote("local-gone", "t1")
+	remote("untracked", "t1")
+
+	files, err := site.Render(remote("identical", "t1"), false)
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	identical := local("identical", Hash(files))
+
+	state := &State{Items: map[string]Entry{
+		"tags/same":         {UpdatedAt: "t1", Hash: "h"},
+		"tags/remote-edit":  {UpdatedAt: "t1", Hash: "h"},
+		"tags/local-edit":   {UpdatedAt: "t1", Hash: "h"},
+		"tags/both-edit":    {UpdatedAt: "t1", Hash: "h"},
+		"tags/local-gone":   {UpdatedAt: "t1", Hash: "h"},
+		"tags/remote-gone":  {UpdatedAt: "t1", Hash: "h"},
+		"tags/both-gone":    {UpdatedAt: "t1", Hash: "h"},
+		"tags/gone-changed": {UpdatedAt: "t1", Hash: "h"},
+	}}
+	locals := map[string]*Local{}
+	for _, item := range []*Local{
+		local("same", "h"),
+		local("remote-edit", "h"),
+		local("local-edit", "h2"),
+		local("both-edit", "h2"),
+		local("remote-gone", "h"),
+		local("gone-changed", "h2"),
+		local("local-new", "h"),
+		local("untracked", "h"),
+		identical,
+	} {
+		locals[item.Key] = item
+	}
+
+	expected := map[string]Status{
+		"tags/remote-edit":  StatusRemoteModified,
+		"tags/local-edit":   StatusModified,
+		"tags/both-edit":    StatusConflict,
+		"tags/remote-new":   StatusRemoteAdded,
+		"tags/local-gone":   StatusDeleted,
+		"tags/remote-gone":  StatusRemoteDeleted,
+		"tags/gone-changed": StatusConflict,
+		"tags/local-new":    StatusAdded,
+		"tags/untracked":    StatusConflict,
+	}
+
+	changes := Compare(state, locals, site)
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %+v", len(expected), changes)
+	}
+	for _, change := range changes {
+		if expected[change.Key] != change.Status {
+			t.Fatalf("expected %s to be %q, got %q", change.Key, expected[change.Key], change.Status)
+		}
+	}
+
+	if _, ok := state.Items["tags/both-gone"]; ok {
+		t.Fatalf("expected a record gone on both sides to leave the state")
+	}
+	if entry := state.Items["tags/identical"]; entry.Hash != identical.Hash || entry.UpdatedAt != "t1" {
+		t.Fatalf("expected an identical untracked record to be adopted, got %+v", entry)
+	}
+}
+
+func TestScanLocalFindsEveryLocale(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "posts"), 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	for _, name := range []string{"a.vi.md", "a.en.md", "a.ja.md", "a.ko.md", "a.json", "notes.md"} {
+		if err := os.WriteFile(filepath.Join(dir, "posts", name), []byte(name), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	items, err := ScanLocal(dir)
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+
+	post := items["posts/a"]
+	expected := []string{"posts/a.en.md", "posts/a.ja.md", "posts/a.ko.md", "posts/a.vi.md"}
+	if len(items) != 1 || post == nil || !post.Markdown || !slices.Equal(post.Files, expected) {
+		t.Fatalf("expected one Markdown post with %v, got %+v", expected, items)
+	}
+}
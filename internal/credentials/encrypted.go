@@ -0,0 +1,185 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	encryptedFileName   = "credentials.enc"
+	encryptedFileFormat = 1
+	kdfName             = "pbkdf2-sha256"
+	kdfIterations       = 600_000
+	kdfSaltSize         = 16
+	kdfKeySize          = 32
+)
+
+// encryptedEnvelope is the on-disk layout of credentials.enc. The sealed
+// plaintext is a JSON object of profile name to token.
+type encryptedEnvelope struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// encryptedFileStore keeps every token in one AES-256-GCM sealed file whose
+// key is derived from a passphrase with PBKDF2.
+type encryptedFileStore struct {
+	path       string
+	passphrase string
+}
+
+func newEncryptedFileStore(options Options) (Store, error) {
+	if options.Dir == "" {
+		return nil, errors.New("encrypted credential store requires a directory")
+	}
+	if options.Passphrase == "" {
+		return nil, errors.New("encrypted credential store requires a passphrase")
+	}
+
+	return &encryptedFileStore{
+		path:       filepath.Join(options.Dir, encryptedFileName),
+		passphrase: options.Passphrase,
+	}, nil
+}
+
+func (s *encryptedFileStore) Get(profile string) (string, error) {
+	tokens, err := s.load()
+	if err != nil {
+		return "", err
+	}
+
+	token, ok := tokens[profile]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return token, nil
+}
+
+func (s *encryptedFileStore) Set(profile string, token string) error {
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	tokens[profile] = token
+
+	return s.save(tokens)
+}
+
+func (s *encryptedFileStore) Delete(profile string) error {
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := tokens[profile]; !ok {
+		return nil
+	}
+
+	delete(tokens, profile)
+
+	return s.save(tokens)
+}
+
+func (s *encryptedFileStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]string{}, nil
+		}
+
+		return nil, err
+	}
+
+	var envelope encryptedEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid encrypted credentials file: %w", err)
+	}
+	if envelope.Version != encryptedFileFormat || envelope.KDF != kdfName {
+		return nil, fmt.Errorf("unsupported encrypted credentials format %d (%s)", envelope.Version, envelope.KDF)
+	}
+
+	aead, err := s.cipher(envelope.Salt, envelope.Iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, envelope.Nonce, envelope.Ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt credentials: wrong passphrase or corrupted file")
+	}
+
+	tokens := map[string]string{}
+	if err := json.Unmarshal(plaintext, &tokens); err != nil {
+		return nil, fmt.Errorf("invalid decrypted credentials: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (s *encryptedFileStore) save(tokens map[string]string) error {
+	plaintext, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, kdfSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+
+	aead, err := s.cipher(salt, kdfIterations)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(encryptedEnvelope{
+		Version:    encryptedFileFormat,
+		KDF:        kdfName,
+		Iterations: kdfIterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, data, 0o600)
+}
+
+func (s *encryptedFileStore) cipher(salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, s.passphrase, salt, iterations, kdfKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,96 @@
+package credentials
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// helperStore delegates to an external command in the style of git
+// credential helpers. The command is run through the shell with the action
+// (get, store or erase) appended, and exchanges key=value lines on
+// stdin/stdout:
+//
+//	profile=<name>
+//	token=<token>   (store input, get output)
+type helperStore struct {
+	command string
+}
+
+func newHelperStore(options Options) (Store, error) {
+	if strings.TrimSpace(options.Helper) == "" {
+		return nil, errors.New("credential helper command is required")
+	}
+
+	return helperStore{command: options.Helper}, nil
+}
+
+func (s helperStore) Get(profile string) (string, error) {
+	stdout, err := s.run("get", map[string]string{"profile": profile})
+	if err != nil {
+		return "", err
+	}
+
+	token := parseHelperOutput(stdout)["token"]
+	if token == "" {
+		return "", ErrNotFound
+	}
+
+	return token, nil
+}
+
+func (s helperStore) Set(profile string, token string) error {
+	_, err := s.run("store", map[string]string{"profile": profile, "token": token})
+
+	return err
+}
+
+func (s helperStore) Delete(profile string) error {
+	_, err := s.run("erase", map[string]string{"profile": profile})
+
+	return err
+}
+
+func (s helperStore) run(action string, input map[string]string) ([]byte, error) {
+	var stdin bytes.Buffer
+	for _, key := range []string{"profile", "token"} {
+		if value, ok := input[key]; ok {
+			fmt.Fprintf(&stdin, "%s=%s\n", key, value)
+		}
+	}
+	stdin.WriteString("\n")
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("sh", "-c", s.command+" "+action)
+	cmd.Stdin = &stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+
+		return nil, fmt.Errorf("credential helper %s failed: %s", action, message)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+func parseHelperOutput(data []byte) map[string]string {
+	values := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if ok {
+			values[strings.TrimSpace(key)] = value
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,39 @@
+package credentials
+
+import (
+	"errors"
+
+	"github.com/zalando/go-keyring"
+)
+
+const keyringService = "geda-cli"
+
+// keyringStore uses the OS keyring: Secret Service over D-Bus on Linux,
+// Keychain on macOS and Credential Manager on Windows.
+type keyringStore struct{}
+
+func newKeyringStore(Options) (Store, error) {
+	return keyringStore{}, nil
+}
+
+func (keyringStore) Get(profile string) (string, error) {
+	token, err := keyring.Get(keyringService, profile)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	}
+
+	return token, err
+}
+
+func (keyringStore) Set(profile string, token string) error {
+	return keyring.Set(keyringService, profile, token)
+}
+
+func (keyringStore) Delete(profile string) error {
+	err := keyring.Delete(keyringService, profile)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
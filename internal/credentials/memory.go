@@ -0,0 +1,43 @@
+package credentials
+
+import "sync"
+
+// MemoryStore is an in-memory Store for tests.
+type MemoryStore struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tokens: map[string]string{}}
+}
+
+func (s *MemoryStore) Get(profile string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[profile]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return token, nil
+}
+
+func (s *MemoryStore) Set(profile string, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[profile] = token
+
+	return nil
+}
+
+func (s *MemoryStore) Delete(profile string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tokens, profile)
+
+	return nil
+}
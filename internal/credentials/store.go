@@ -0,0 +1,95 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+const (
+	// Plaintext keeps the token inline in config.json; it is handled by the
+	// config package and has no Store implementation.
+	Plaintext     = "file"
+	Keyring       = "keyring"
+	EncryptedFile = "encrypted-file"
+	Helper        = "helper"
+)
+
+var ErrNotFound = errors.New("credential not found")
+
+// Store keeps access tokens outside config.json, keyed by profile name.
+type Store interface {
+	Get(profile string) (string, error)
+	Set(profile string, token string) error
+	Delete(profile string) error
+}
+
+type Options struct {
+	// Dir is the directory holding the encrypted credentials file.
+	Dir string
+	// Helper is the credential-helper command line.
+	Helper string
+	// Passphrase unlocks the encrypted credentials file.
+	Passphrase string
+}
+
+type Factory func(options Options) (Store, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{
+		Keyring:       newKeyringStore,
+		EncryptedFile: newEncryptedFileStore,
+		Helper:        newHelperStore,
+	}
+)
+
+// Register adds or replaces a backend. Tests use it to install an in-memory
+// fake.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[name] = factory
+}
+
+// Unregister removes a backend added with Register.
+func Unregister(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	delete(registry, name)
+}
+
+func Open(name string, options Options) (Store, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown credential store %q", name)
+	}
+
+	return factory(options)
+}
+
+// IsExternal reports whether the named backend keeps tokens outside
+// config.json.
+func IsExternal(name string) bool {
+	return name != "" && name != Plaintext
+}
+
+// Names returns every selectable backend, including the plaintext fallback.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := []string{Plaintext}
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+
+	return names
+}
@@ -0,0 +1,145 @@
+package credentials
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestMemoryStore(t *testing.T) {
+	assertStoreRoundTrip(t, NewMemoryStore())
+}
+
+func TestKeyringStore(t *testing.T) {
+	keyring.MockInit()
+
+	store, err := Open(Keyring, Options{})
+	if err != nil {
+		t.Fatalf("failed to open keyring store: %v", err)
+	}
+
+	assertStoreRoundTrip(t, store)
+}
+
+func TestEncryptedFileStore(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := Open(EncryptedFile, Options{Dir: dir, Passphrase: "correct horse"})
+	if err != nil {
+		t.Fatalf("failed to open encrypted store: %v", err)
+	}
+
+	assertStoreRoundTrip(t, store)
+
+	if err := store.Set("prod", "prod-token"); err != nil {
+		t.Fatalf("failed to set token: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, encryptedFileName))
+	if err != nil {
+		t.Fatalf("expected encrypted file to exist: %v", err)
+	}
+	if strings.Contains(string(data), "prod-token") {
+		t.Fatal("expected token to be encrypted on disk")
+	}
+
+	wrong, err := Open(EncryptedFile, Options{Dir: dir, Passphrase: "wrong"})
+	if err != nil {
+		t.Fatalf("failed to open encrypted store: %v", err)
+	}
+	if _, err := wrong.Get("prod"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected decryption error, got %v", err)
+	}
+
+	if _, err := Open(EncryptedFile, Options{Dir: dir}); err == nil {
+		t.Fatal("expected missing passphrase error")
+	}
+}
+
+func TestHelperStore(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "helper.sh")
+	content := `#!/bin/sh
+db="` + dir + `/db"
+touch "$db"
+profile=""
+token=""
+while IFS='=' read -r key value; do
+  [ -z "$key" ] && break
+  case "$key" in
+    profile) profile="$value" ;;
+    token) token="$value" ;;
+  esac
+done
+case "$1" in
+  get) grep "^$profile=" "$db" | head -n 1 | sed "s/^$profile=/token=/" ;;
+  store) grep -v "^$profile=" "$db" > "$db.tmp"; echo "$profile=$token" >> "$db.tmp"; mv "$db.tmp" "$db" ;;
+  erase) grep -v "^$profile=" "$db" > "$db.tmp"; mv "$db.tmp" "$db" ;;
+esac
+`
+	if err := os.WriteFile(script, []byte(content), 0o700); err != nil {
+		t.Fatalf("failed to write helper script: %v", err)
+	}
+
+	store, err := Open(Helper, Options{Helper: script})
+	if err != nil {
+		t.Fatalf("failed to open helper store: %v", err)
+	}
+
+	assertStoreRoundTrip(t, store)
+
+	if _, err := Open(Helper, Options{}); err == nil {
+		t.Fatal("expected missing helper command error")
+	}
+}
+
+func TestOpenUnknownStore(t *testing.T) {
+	if _, err := Open("unknown", Options{}); err == nil {
+		t.Fatal("expected unknown store error")
+	}
+	if IsExternal(Plaintext) || IsExternal("") || !IsExternal(Keyring) {
+		t.Fatal("unexpected IsExternal result")
+	}
+}
+
+func assertStoreRoundTrip(t *testing.T, store Store) {
+	t.Helper()
+
+	if _, err := store.Get("staging"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for missing token, got %v", err)
+	}
+
+	if err := store.Set("staging", "staging-token"); err != nil {
+		t.Fatalf("failed to set token: %v", err)
+	}
+	if err := store.Set("local", "local-token"); err != nil {
+		t.Fatalf("failed to set token: %v", err)
+	}
+
+	token, err := store.Get("staging")
+	if err != nil {
+		t.Fatalf("failed to get token: %v", err)
+	}
+	if token != "staging-token" {
+		t.Fatalf("expected staging-token, got %q", token)
+	}
+
+	if err := store.Delete("staging"); err != nil {
+		t.Fatalf("failed to delete token: %v", err)
+	}
+	if err := store.Delete("staging"); err != nil {
+		t.Fatalf("expected deleting a missing token to succeed, got %v", err)
+	}
+	if _, err := store.Get("staging"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+
+	token, err = store.Get("local")
+	if err != nil || token != "local-token" {
+		t.Fatalf("expected other token to be kept, got %q (%v)", token, err)
+	}
+}
@@ -0,0 +1,208 @@
+// Package diff compares a local payload with the record the API holds, field
+// by field.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type Op string
+
+const (
+	OpAdd    Op = "add"
+	OpRemove Op = "remove"
+	OpChange Op = "change"
+)
+
+// Change is one differing field. Path is dotted, so a changed English title
+// is reported as "title.en".
+type Change struct {
+	Path string `json:"path"`
+	Op   Op     `json:"op"`
+	Old  any    `json:"old"`
+	New  any    `json:"new"`
+}
+
+// ServerFields are set by the API and never sent by the CLI, so they are
+// left out of every comparison.
+var ServerFields = []string{"id", "created_at", "updated_at", "deleted_at"}
+
+// Normalize returns record as plain decoded JSON without ServerFields.
+func Normalize(record map[string]any) map[string]any {
+	normalized := map[string]any{}
+
+	encoded, err := json.Marshal(record)
+	if err != nil || json.Unmarshal(encoded, &normalized) != nil {
+		normalized = map[string]any{}
+		for key, value := range record {
+			normalized[key] = value
+		}
+	}
+
+	for _, field := range ServerFields {
+		delete(normalized, field)
+	}
+
+	return normalized
+}
+
+// WritePayload returns record normalized into the shape the API accepts on
+// a write: the nested category becomes category_id and tag objects become
+// their ids.
+func WritePayload(record map[string]any) map[string]any {
+	payload := Normalize(record)
+
+	if category, ok := payload["category"].(map[string]any); ok {
+		if _, exists := payload["category_id"]; !exists {
+			payload["category_id"] = category["id"]
+		}
+		delete(payload, "category")
+	}
+
+	if tags, ok := payload["tags"].([]any); ok {
+		ids := make([]any, 0, len(tags))
+		for _, tag := range tags {
+			if object, ok := tag.(map[string]any); ok {
+				tag = object["id"]
+			}
+			ids = append(ids, tag)
+		}
+		payload["tags"] = ids
+	}
+
+	return payload
+}
+
+// Compare lists what applying local would change in remote. Both sides are
+// compared in write shape, so a remote category or tag object matches the
+// id sent for it. Top-level fields missing from local are kept by the API
+// and not reported; inside a field that local does send, such as a
+// bilingual title, every key is compared. A nil remote means the record
+// does not exist yet.
+func Compare(remote map[string]any, local map[string]any) []Change {
+	remote = WritePayload(remote)
+	local = WritePayload(local)
+
+	changes := []Change{}
+	for _, key := range sortedKeys(local) {
+		old, exists := remote[key]
+		changes = compare(changes, key, old, exists, local[key])
+	}
+
+	return changes
+}
+
+func compare(changes []Change, path string, old any, exists bool, value any) []Change {
+	if !exists {
+		return append(changes, Change{Path: path, Op: OpAdd, New: value})
+	}
+
+	oldMap, oldIsMap := old.(map[string]any)
+	newMap, newIsMap := value.(map[string]any)
+	if oldIsMap && newIsMap {
+		keys := sortedKeys(oldMap)
+		for _, key := range sortedKeys(newMap) {
+			if _, ok := oldMap[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			nested, ok := newMap[key]
+			if !ok {
+				changes = append(changes, Change{Path: path + "." + key, Op: OpRemove, Old: oldMap[key]})
+
+				continue
+			}
+
+			previous, ok := oldMap[key]
+			changes = compare(changes, path+"."+key, previous, ok, nested)
+		}
+
+		return changes
+	}
+
+	if reflect.DeepEqual(old, value) {
+		return changes
+	}
+
+	return append(changes, Change{Path: path, Op: OpChange, Old: old, New: value})
+}
+
+func sortedKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+const (
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorReset  = "\x1b[0m"
+)
+
+// Write prints changes one field at a time, with old values prefixed by "-"
+// and new values by "+". color adds ANSI colors for terminals.
+func Write(w io.Writer, changes []Change, color bool) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "no changes")
+
+		return err
+	}
+
+	paint := func(code string, text string) string {
+		if !color {
+			return text
+		}
+
+		return code + text + colorReset
+	}
+
+	for _, change := range changes {
+		lines := []string{}
+		switch change.Op {
+		case OpAdd:
+			lines = append(lines, paint(colorGreen, "+ "+change.Path+": "+valueText(change.New)))
+		case OpRemove:
+			lines = append(lines, paint(colorRed, "- "+change.Path+": "+valueText(change.Old)))
+		default:
+			lines = append(lines,
+				paint(colorYellow, "~ "+change.Path),
+				paint(colorRed, "  - "+valueText(change.Old)),
+				paint(colorGreen, "  + "+valueText(change.New)),
+			)
+		}
+
+		for _, line := range lines {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// valueText encodes value as compact JSON, leaving HTML in post bodies
+// readable.
+func valueText(value any) string {
+	var buffer strings.Builder
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
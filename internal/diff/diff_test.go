@@ -0,0 +1,87 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompareReportsPerLocaleChanges(t *testing.T) {
+	remote := map[string]any{
+		"id":         7,
+		"slug":       "xin-chao",
+		"updated_at": "2026-01-01T00:00:00Z",
+		"status":     "draft",
+		"title":      map[string]any{"vi": "Xin chào", "en": "Hello"},
+		"body":       map[string]any{"vi": "<p>Cũ</p>", "en": "<p>Old</p>"},
+		"views":      12,
+	}
+	local := map[string]any{
+		"id":     1,
+		"slug":   "xin-chao",
+		"status": "published",
+		"title":  map[string]any{"vi": "Xin chào", "en": "Hello there"},
+		"body":   map[string]any{"vi": "<p>Cũ</p>"},
+		"tags":   []any{1, 2},
+	}
+
+	changes := Compare(remote, local)
+
+	expected := []Change{
+		{Path: "body.en", Op: OpRemove, Old: "<p>Old</p>"},
+		{Path: "status", Op: OpChange, Old: "draft", New: "published"},
+		{Path: "tags", Op: OpAdd, New: []any{float64(1), float64(2)}},
+		{Path: "title.en", Op: OpChange, Old: "Hello", New: "Hello there"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %#v", len(expected), changes)
+	}
+	for i, change := range expected {
+		if changes[i].Path != change.Path || changes[i].Op != change.Op {
+			t.Fatalf("expected change %d to be %s %s, got %#v", i, change.Op, change.Path, changes[i])
+		}
+	}
+}
+
+func TestCompareWithoutRemoteAddsEveryField(t *testing.T) {
+	changes := Compare(nil, map[string]any{"slug": "new", "title": map[string]any{"vi": "Mới"}})
+
+	if len(changes) != 2 || changes[0].Op != OpAdd || changes[1].Path != "title" {
+		t.Fatalf("unexpected changes: %#v", changes)
+	}
+}
+
+func TestCompareUnchangedPostWithRelations(t *testing.T) {
+	remote := map[string]any{
+		"id":          7,
+		"slug":        "xin-chao",
+		"category_id": 3,
+		"category":    map[string]any{"id": 3, "slug": "news"},
+		"tags":        []any{map[string]any{"id": 1, "slug": "go"}, map[string]any{"id": 2, "slug": "cli"}},
+	}
+	local := map[string]any{"slug": "xin-chao", "category_id": 3, "tags": []any{1, 2}}
+
+	if changes := Compare(remote, local); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %#v", changes)
+	}
+
+	// A record pulled in read shape compares the same way.
+	if changes := Compare(remote, remote); len(changes) != 0 {
+		t.Fatalf("expected no changes against itself, got %#v", changes)
+	}
+}
+
+func TestWriteWithoutColor(t *testing.T) {
+	var buffer strings.Builder
+	err := Write(&buffer, []Change{
+		{Path: "title.en", Op: OpChange, Old: "Hello", New: "Hi"},
+		{Path: "body.vi", Op: OpAdd, New: "<p>Mới</p>"},
+	}, false)
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	expected := "~ title.en\n  - \"Hello\"\n  + \"Hi\"\n+ body.vi: \"<p>Mới</p>\"\n"
+	if buffer.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buffer.String())
+	}
+}
@@ -0,0 +1,507 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"geda-cli/internal/importer"
+	"geda-cli/pkg/geda"
+
+	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
+)
+
+var Resources = []string{"posts", "pages", "products", "categories", "tags"}
+
+const (
+	ManifestFile = "manifest.json"
+	SettingsFile = "settings.json"
+	MediaDir     = "media"
+
+	manifestVersion = 1
+	perPage         = 100
+)
+
+var mediaExtensions = map[string]bool{
+	".avif": true, ".gif": true, ".jpeg": true, ".jpg": true, ".png": true, ".svg": true, ".webp": true,
+	".mp3": true, ".mp4": true, ".pdf": true, ".webm": true,
+}
+
+var (
+	absoluteLink = regexp.MustCompile(`https?://[^\s"'<>()]+`)
+	relativeLink = regexp.MustCompile(`(?:src|href)\s*=\s*["'](/[^"']+)["']`)
+)
+
+type Options struct {
+	Dir      string
+	Media    bool
+	Markdown bool
+}
+
+// Files maps every written path, slash-separated and relative to the
+// export directory, to its SHA-256 checksum.
+type Manifest struct {
+	Version    int               `json:"version"`
+	BaseURL    string            `json:"base_url"`
+	ExportedAt string            `json:"exported_at"`
+	Counts     map[string]int    `json:"counts"`
+	Media      map[string]string `json:"media,omitempty"`
+	Files      map[string]string `json:"files"`
+	// Warnings do not fail the export; a post that could not be converted
+	// to Markdown is still exported as JSON.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func Export(ctx context.Context, client *geda.Client, opts Options) (*Manifest, error) {
+	if strings.TrimSpace(opts.Dir) == "" {
+		return nil, errors.New("export directory is required")
+	}
+
+	previous, err := readManifest(filepath.Join(opts.Dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		Version:    manifestVersion,
+		BaseURL:    client.BaseURL(),
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Counts:     map[string]int{},
+		Files:      map[string]string{},
+	}
+	export := &export{dir: opts.Dir, manifest: manifest}
+
+	records := map[string][]map[string]any{}
+	for _, resource := range Resources {
+		names := map[string]map[string]any{}
+		pager := client.Resource(resource).Pages(&geda.ListOptions{PerPage: perPage})
+		for pager.Next(ctx) {
+			for _, item := range pager.RawItems() {
+				record, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+
+				name := FileName(record)
+				if other, ok := names[name]; ok {
+					return nil, fmt.Errorf("%s %v and %v are both stored as %s", resource, recordName(other), recordName(record), path.Join(resource, name+".json"))
+				}
+				names[name] = record
+
+				if err := export.writeJSON(path.Join(resource, name+".json"), record); err != nil {
+					return nil, err
+				}
+				records[resource] = append(records[resource], record)
+			}
+		}
+		if err := pager.Err(); err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", resource, err)
+		}
+
+		manifest.Counts[resource] = len(records[resource])
+	}
+
+	settings, _, err := client.Settings.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settings: %w", err)
+	}
+
+	values := map[string]any{}
+	for _, setting := range settings {
+		values[setting.Key] = setting.Value
+	}
+	if err := export.writeJSON(SettingsFile, values); err != nil {
+		return nil, err
+	}
+	manifest.Counts["settings"] = len(values)
+
+	if opts.Markdown {
+		if err := export.writeMarkdown(records); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Media {
+		if err := export.downloadMedia(ctx, client, records); err != nil {
+			return nil, err
+		}
+	}
+
+	if previous != nil {
+		for file := range previous.Files {
+			// The manifest is a local file anyone can edit; never delete
+			// outside the export directory.
+			if !filepath.IsLocal(filepath.FromSlash(file)) {
+				continue
+			}
+			if _, ok := manifest.Files[file]; !ok {
+				if err := os.Remove(filepath.Join(opts.Dir, filepath.FromSlash(file))); err != nil && !errors.Is(err, os.ErrNotExist) {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	encoded, err := EncodeJSON(manifest)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(opts.Dir, ManifestFile), encoded, 0o644); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+type export struct {
+	dir      string
+	manifest *Manifest
+}
+
+func (e *export) writeJSON(name string, value any) error {
+	encoded, err := EncodeJSON(value)
+	if err != nil {
+		return err
+	}
+
+	return e.writeFile(name, encoded)
+}
+
+func (e *export) writeFile(name string, content []byte) error {
+	target := filepath.Join(e.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(target, content, 0o644); err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(content)
+	e.manifest.Files[name] = "sha256:" + hex.EncodeToString(sum[:])
+
+	return nil
+}
+
+func (e *export) moveFile(from string, name string, sum []byte) error {
+	target := filepath.Join(e.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	if err := os.Chmod(from, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(from, target); err != nil {
+		return err
+	}
+
+	e.manifest.Files[name] = "sha256:" + hex.EncodeToString(sum)
+
+	return nil
+}
+
+func (e *export) writeMarkdown(records map[string][]map[string]any) error {
+	lookups := NewLookups(records["categories"], records["tags"])
+
+	for _, record := range records["posts"] {
+		documents, err := PostMarkdown(record, lookups)
+		if err != nil {
+			e.manifest.Warnings = append(e.manifest.Warnings, fmt.Sprintf("skipped Markdown for post %s: %v", FileName(record), err))
+
+			continue
+		}
+
+		for locale, content := range documents {
+			if err := e.writeFile(path.Join("posts", FileName(record)+"."+locale+".md"), content); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+type Lookups struct {
+	Categories map[geda.ID]string
+	Tags       map[geda.ID]string
+}
+
+func NewLookups(categories []map[string]any, tags []map[string]any) Lookups {
+	return Lookups{Categories: slugsByID(categories), Tags: slugsByID(tags)}
+}
+
+func PostMarkdown(record map[string]any, lookups Lookups) (map[string][]byte, error) {
+	post := geda.Post{}
+	if err := decode(record, &post); err != nil {
+		return nil, err
+	}
+
+	categorySlug := lookups.Categories[post.CategoryID]
+	if post.Category != nil && post.Category.Slug != "" {
+		categorySlug = post.Category.Slug
+	}
+
+	tags := []string{}
+	for _, tag := range post.Tags {
+		if tag.Slug == "" {
+			tag.Slug = lookups.Tags[tag.ID]
+		}
+		if tag.Slug != "" {
+			tags = append(tags, tag.Slug)
+		}
+	}
+
+	documents := map[string][]byte{}
+	for _, locale := range postLocales(post) {
+		frontMatter := importer.FrontMatter{
+			Slug:            post.Slug,
+			Title:           post.Title.Get(locale),
+			Excerpt:         post.Excerpt.Get(locale),
+			CategorySlug:    categorySlug,
+			Status:          post.Status,
+			Tags:            tags,
+			MetaTitle:       post.MetaTitle.Get(locale),
+			MetaDescription: post.MetaDescription.Get(locale),
+			FeaturedImage:   post.FeaturedImage,
+			OGImage:         post.OGImage,
+			PublishedAt:     post.PublishedAt,
+			ScheduledAt:     post.ScheduledAt,
+		}
+		if post.IsFeatured != nil && *post.IsFeatured {
+			frontMatter.IsFeatured = post.IsFeatured
+		}
+
+		body, err := htmltomarkdown.ConvertString(post.Body.Get(locale))
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert body to Markdown: %w", err)
+		}
+
+		content, err := importer.FormatMarkdown(frontMatter, body)
+		if err != nil {
+			return nil, err
+		}
+
+		documents[locale] = content
+	}
+
+	return documents, nil
+}
+
+func postLocales(post geda.Post) []string {
+	seen := map[string]bool{}
+	locales := []string{}
+	add := func(locale string) {
+		if !seen[locale] {
+			seen[locale] = true
+			locales = append(locales, locale)
+		}
+	}
+
+	for _, locale := range importer.DefaultLocales.Required {
+		add(locale)
+	}
+	for _, field := range []geda.Localized{post.Title, post.Excerpt, post.Body, post.MetaTitle, post.MetaDescription} {
+		for locale, text := range field {
+			if text != "" && importer.ValidLocale(locale) {
+				add(locale)
+			}
+		}
+	}
+
+	return locales
+}
+
+func (e *export) downloadMedia(ctx context.Context, client *geda.Client, records map[string][]map[string]any) error {
+	links := map[string]string{}
+	for _, resource := range Resources {
+		for _, record := range records[resource] {
+			collectLinks(record, client.BaseURL(), links)
+		}
+	}
+
+	sorted := make([]string, 0, len(links))
+	for link := range links {
+		sorted = append(sorted, link)
+	}
+	sort.Strings(sorted)
+
+	e.manifest.Media = map[string]string{}
+	for _, link := range sorted {
+		file, err := os.CreateTemp(e.dir, ".download-*")
+		if err != nil {
+			return err
+		}
+
+		hash := sha256.New()
+		downloadErr := client.Media.Download(ctx, link, io.MultiWriter(file, hash))
+		if err := file.Close(); err != nil {
+			os.Remove(file.Name())
+
+			return err
+		}
+		if err := downloadErr; err != nil {
+			os.Remove(file.Name())
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			e.manifest.Warnings = append(e.manifest.Warnings, fmt.Sprintf("failed to download %s: %v", link, err))
+
+			continue
+		}
+
+		name := path.Join(MediaDir, links[link])
+		if err := e.moveFile(file.Name(), name, hash.Sum(nil)); err != nil {
+			os.Remove(file.Name())
+
+			return err
+		}
+		e.manifest.Media[link] = name
+	}
+	e.manifest.Counts["media"] = len(e.manifest.Media)
+
+	return nil
+}
+
+func collectLinks(value any, baseURL string, links map[string]string) {
+	switch typed := value.(type) {
+	case map[string]any:
+		for _, nested := range typed {
+			collectLinks(nested, baseURL, links)
+		}
+	case []any:
+		for _, nested := range typed {
+			collectLinks(nested, baseURL, links)
+		}
+	case string:
+		candidates := absoluteLink.FindAllString(typed, -1)
+		for _, match := range relativeLink.FindAllStringSubmatch(typed, -1) {
+			candidates = append(candidates, match[1])
+		}
+		if strings.HasPrefix(typed, "/") && !strings.ContainsAny(typed, " \n<>\"") {
+			candidates = append(candidates, typed)
+		}
+
+		for _, candidate := range candidates {
+			if local, ok := mediaPath(candidate, baseURL); ok {
+				links[candidate] = local
+			}
+		}
+	}
+}
+
+func mediaPath(link string, baseURL string) (string, bool) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", false
+	}
+
+	target, err := url.Parse(link)
+	if err != nil || (target.Host != "" && target.Host != base.Host) {
+		return "", false
+	}
+
+	if !mediaExtensions[strings.ToLower(path.Ext(target.Path))] {
+		return "", false
+	}
+
+	cleaned := strings.TrimPrefix(path.Clean("/"+target.Path), "/")
+	if cleaned == "" {
+		return "", false
+	}
+
+	return cleaned, true
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Unsafe characters become "-", so different slugs such as "a b" and "a-b"
+// can share a name.
+func FileName(record map[string]any) string {
+	name, _ := record["slug"].(string)
+	if name == "" {
+		name = fmt.Sprintf("id-%v", record["id"])
+	}
+
+	name = strings.Trim(unsafeFileChars.ReplaceAllString(name, "-"), ".")
+	if name == "" {
+		name = "unnamed"
+	}
+
+	return name
+}
+
+func recordName(record map[string]any) string {
+	if slug, _ := record["slug"].(string); slug != "" {
+		return strconv.Quote(slug)
+	}
+
+	return fmt.Sprintf("id %v", record["id"])
+}
+
+func slugsByID(records []map[string]any) map[geda.ID]string {
+	slugs := map[geda.ID]string{}
+	for _, record := range records {
+		var id geda.ID
+		if err := decode(record["id"], &id); err != nil {
+			continue
+		}
+		slug, _ := record["slug"].(string)
+		slugs[id] = slug
+	}
+
+	return slugs
+}
+
+func readManifest(filePath string) (*Manifest, error) {
+	content, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestFile, err)
+	}
+
+	return manifest, nil
+}
+
+// Keys are sorted so repeated exports of unchanged content produce
+// identical files.
+func EncodeJSON(value any) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func decode(value any, target any) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(encoded, target)
+}
@@ -0,0 +1,215 @@
+package exporter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"geda-cli/internal/importer"
+	"geda-cli/internal/mockserver"
+	"geda-cli/pkg/geda"
+)
+
+func TestExportWritesRecordsMediaMarkdownAndManifest(t *testing.T) {
+	ctx := context.Background()
+
+	mock := mockserver.New()
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	client := geda.NewClient(server.URL, "token")
+
+	imagePath := filepath.Join(t.TempDir(), "cover.png")
+	if err := os.WriteFile(imagePath, []byte("\x89PNG\r\n\x1a\nimage"), 0o600); err != nil {
+		t.Fatalf("failed to write image: %v", err)
+	}
+	media, _, err := client.Media.Upload(ctx, imagePath, geda.Localized{})
+	if err != nil {
+		t.Fatalf("failed to upload media: %v", err)
+	}
+
+	category, _, err := client.Categories.Create(ctx, geda.Category{Slug: "tin-tuc"})
+	if err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+	tag, _, err := client.Tags.Create(ctx, geda.Tag{Slug: "ai"})
+	if err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+	for _, slug := range []string{"xin-chao", "tam-biet"} {
+		_, _, err = client.Posts.Create(ctx, map[string]any{
+			"slug":           slug,
+			"status":         "published",
+			"category_id":    category.ID,
+			"tags":           []geda.ID{tag.ID},
+			"featured_image": media.URL,
+			"title":          map[string]any{"vi": "Xin chào", "en": "Hello"},
+			"body": map[string]any{
+				"vi": `<h2>Mở đầu</h2><p><img src="` + media.URL + `" alt="bìa"></p>`,
+				"en": `<h2>Intro</h2><p>See <a href="https://example.com/a.png">elsewhere</a>.</p>`,
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to create post: %v", err)
+		}
+	}
+	if _, _, err := client.Settings.Set(ctx, "site_name", "GEDA"); err != nil {
+		t.Fatalf("failed to set setting: %v", err)
+	}
+
+	dir := t.TempDir()
+	manifest, err := Export(ctx, client, Options{Dir: dir, Media: true, Markdown: true})
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	expectedCounts := map[string]int{"posts": 2, "categories": 1, "tags": 1, "pages": 0, "products": 0, "settings": 1, "media": 1}
+	for key, count := range expectedCounts {
+		if manifest.Counts[key] != count {
+			t.Fatalf("expected %s count %d, got %v", key, count, manifest.Counts)
+		}
+	}
+	if len(manifest.Warnings) != 0 {
+		t.Fatalf("expected no warnings, got %v", manifest.Warnings)
+	}
+
+	for name, checksum := range manifest.Files {
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("expected %s to be written: %v", name, err)
+		}
+
+		sum := sha256.Sum256(content)
+		if checksum != "sha256:"+hex.EncodeToString(sum[:]) {
+			t.Fatalf("checksum mismatch for %s", name)
+		}
+	}
+
+	mediaFile := manifest.Media[media.URL]
+	if !strings.HasPrefix(mediaFile, "media/storage/") {
+		t.Fatalf("expected the uploaded image below media/, got %v", manifest.Media)
+	}
+	if content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(mediaFile))); err != nil || string(content) != "\x89PNG\r\n\x1a\nimage" {
+		t.Fatalf("expected the downloaded image, got %q (%v)", content, err)
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(dir, ".download-*")); len(leftovers) != 0 {
+		t.Fatalf("expected no temporary files, got %v", leftovers)
+	}
+
+	var record map[string]any
+	content, err := os.ReadFile(filepath.Join(dir, "posts", "xin-chao.json"))
+	if err != nil {
+		t.Fatalf("failed to read post file: %v", err)
+	}
+	if err := json.Unmarshal(content, &record); err != nil || record["slug"] != "xin-chao" {
+		t.Fatalf("unexpected post file %s: %v", content, err)
+	}
+
+	document, err := importer.ParseMarkdownFile(filepath.Join(dir, "posts", "xin-chao.vi.md"))
+	if err != nil {
+		t.Fatalf("exported Markdown does not import: %v", err)
+	}
+	if document.FrontMatter.CategorySlug != "tin-tuc" || len(document.FrontMatter.Tags) != 1 || document.FrontMatter.Tags[0] != "ai" {
+		t.Fatalf("unexpected front matter: %#v", document.FrontMatter)
+	}
+	if !strings.Contains(document.BodyMD, "## Mở đầu") {
+		t.Fatalf("expected the body as Markdown, got %q", document.BodyMD)
+	}
+
+	if _, err := client.Posts.Delete(ctx, "tam-biet"); err != nil {
+		t.Fatalf("failed to delete post: %v", err)
+	}
+	if _, err := Export(ctx, client, Options{Dir: dir}); err != nil {
+		t.Fatalf("second export failed: %v", err)
+	}
+	for _, name := range []string{"posts/tam-biet.json", "posts/tam-biet.en.md", mediaFile} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name))); !os.IsNotExist(err) {
+			t.Fatalf("expected %s from the previous export to be removed, got %v", name, err)
+		}
+	}
+}
+
+func TestPostMarkdownWritesEveryLocale(t *testing.T) {
+	documents, err := PostMarkdown(map[string]any{
+		"id":    "5",
+		"slug":  "konnichiwa",
+		"title": map[string]any{"vi": "Xin chào", "ja": "こんにちは"},
+		"body":  map[string]any{"vi": "<p>Chào</p>", "ko": "<p>안녕</p>"},
+	}, Lookups{})
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+
+	if len(documents) != 4 || documents["en"] == nil {
+		t.Fatalf("expected vi, en, ja and ko documents, got %d", len(documents))
+	}
+	if !strings.Contains(string(documents["ja"]), "title: こんにちは") || !strings.Contains(string(documents["ko"]), "안녕") {
+		t.Fatalf("unexpected documents:\n%s\n%s", documents["ja"], documents["ko"])
+	}
+}
+
+func TestExportKeepsFilesOutsideDirAndWarnsOnBadPosts(t *testing.T) {
+	mock := mockserver.New()
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+	if err := mock.Seed("posts", []map[string]any{{"slug": "bad", "title": []any{"not", "text"}}}); err != nil {
+		t.Fatalf("failed to seed posts: %v", err)
+	}
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "site")
+	outside := filepath.Join(root, "keep.txt")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(outside, []byte("keep"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(`{"files":{"../keep.txt":"sha256:x"}}`), 0o600); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	manifest, err := Export(context.Background(), geda.NewClient(server.URL, "token"), Options{Dir: dir, Markdown: true})
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	if _, err := os.Stat(outside); err != nil {
+		t.Fatalf("expected a file outside the export directory to be kept: %v", err)
+	}
+	if len(manifest.Warnings) != 1 || !strings.Contains(manifest.Warnings[0], "skipped Markdown for post bad") {
+		t.Fatalf("expected a warning for the bad post, got %v", manifest.Warnings)
+	}
+	if _, ok := manifest.Files["posts/bad.json"]; !ok {
+		t.Fatalf("expected the bad post to be exported as JSON, got %v", manifest.Files)
+	}
+}
+
+func TestExportRefusesRecordsSharingAFileName(t *testing.T) {
+	mock := mockserver.New()
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+	if err := mock.Seed("tags", []map[string]any{{"slug": "a b"}, {"slug": "a-b"}}); err != nil {
+		t.Fatalf("failed to seed tags: %v", err)
+	}
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	_, err := Export(context.Background(), geda.NewClient(server.URL, "token"), Options{Dir: t.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), `tags "a b" and "a-b" are both stored as tags/a-b.json`) {
+		t.Fatalf("expected a file name collision error, got %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+type CassetteMode string
+
+const (
+	CassetteRecord CassetteMode = "record"
+	CassetteReplay CassetteMode = "replay"
+)
+
+var ErrCassetteMismatch = errors.New("no recorded interaction matches request")
+
+// Cassette records request/response pairs to a file, or serves them back
+// in replay mode. Files ending in .json are written as JSON, anything else
+// as YAML. Tokens and passwords are scrubbed before anything is stored.
+type Cassette struct {
+	path string
+	mode CassetteMode
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+type cassetteFile struct {
+	Interactions []Interaction `json:"interactions" yaml:"interactions"`
+}
+
+type Interaction struct {
+	Request  RecordedRequest  `json:"request" yaml:"request"`
+	Response RecordedResponse `json:"response" yaml:"response"`
+}
+
+type RecordedRequest struct {
+	Method      string `json:"method" yaml:"method"`
+	URL         string `json:"url" yaml:"url"`
+	ContentType string `json:"content_type,omitempty" yaml:"content_type,omitempty"`
+	Body        string `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	Status  int               `json:"status" yaml:"status"`
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body    string            `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// OpenCassette starts a fresh recording at path, or loads path for replay.
+func OpenCassette(path string, mode CassetteMode) (*Cassette, error) {
+	cassette := &Cassette{path: path, mode: mode}
+
+	switch mode {
+	case CassetteRecord:
+		return cassette, cassette.save()
+	case CassetteReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var file cassetteFile
+		if cassette.isJSON() {
+			err = json.Unmarshal(data, &file)
+		} else {
+			err = yaml.Unmarshal(data, &file)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+		}
+
+		cassette.interactions = file.Interactions
+		cassette.used = make([]bool, len(file.Interactions))
+
+		return cassette, nil
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %q, expected record or replay", mode)
+	}
+}
+
+// WithCassette records or replays every request through cassette.
+func WithCassette(cassette *Cassette) Option {
+	return func(c *Client) {
+		c.cassette = cassette
+	}
+}
+
+// Transport wraps next so requests are recorded or replayed. next is not
+// used in replay mode.
+func (c *Cassette) Transport(next http.RoundTripper) http.RoundTripper {
+	return cassetteTransport{cassette: c, next: next}
+}
+
+type cassetteTransport struct {
+	cassette *Cassette
+	next     http.RoundTripper
+}
+
+func (t cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.cassette.mode == CassetteReplay {
+		return t.cassette.replay(req, recorded)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	headers := map[string]string{}
+	for name := range resp.Header {
+		if !strings.EqualFold(name, "Set-Cookie") {
+			headers[name] = resp.Header.Get(name)
+		}
+	}
+
+	if err := t.cassette.record(Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			Status:  resp.StatusCode,
+			Headers: headers,
+			Body:    string(redactBody(body)),
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return resp, nil
+}
+
+// recordRequest captures the parts of req used for matching. The URL keeps
+// only path and query so a cassette replays against any base URL, and
+// multipart bodies are left out because their boundaries are random.
+func recordRequest(req *http.Request) (RecordedRequest, error) {
+	recorded := RecordedRequest{
+		Method:      req.Method,
+		URL:         req.URL.RequestURI(),
+		ContentType: req.Header.Get("Content-Type"),
+	}
+
+	if req.Body == nil {
+		return recorded, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return recorded, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	if !strings.HasPrefix(recorded.ContentType, "multipart/") {
+		recorded.Body = string(redactBody(body))
+	}
+	recorded.ContentType = strings.SplitN(recorded.ContentType, ";", 2)[0]
+
+	return recorded, nil
+}
+
+func (c *Cassette) record(interaction Interaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.interactions = append(c.interactions, interaction)
+
+	return c.save()
+}
+
+// replay serves the first unused interaction matching the request, so
+// repeated identical requests are answered in recorded order.
+func (c *Cassette) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, interaction := range c.interactions {
+		if c.used[i] || !interaction.Request.matches(recorded) {
+			continue
+		}
+
+		c.used[i] = true
+
+		header := http.Header{}
+		for name, value := range interaction.Response.Headers {
+			header.Set(name, value)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+			StatusCode:    interaction.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrCassetteMismatch, recorded.Method, recorded.URL)
+}
+
+func (r RecordedRequest) matches(other RecordedRequest) bool {
+	return r.Method == other.Method && r.URL == other.URL && r.Body == other.Body
+}
+
+func (c *Cassette) save() error {
+	file := cassetteFile{Interactions: c.interactions}
+	if file.Interactions == nil {
+		file.Interactions = []Interaction{}
+	}
+
+	var data []byte
+	var err error
+	if c.isJSON() {
+		data, err = json.MarshalIndent(file, "", "  ")
+	} else {
+		data, err = yaml.Marshal(file)
+	}
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(c.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(c.path, data, 0o600)
+}
+
+func (c *Cassette) isJSON() bool {
+	return strings.EqualFold(filepath.Ext(c.path), ".json")
+}
@@ -0,0 +1,101 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassetteRecordsAndReplays(t *testing.T) {
+	for _, name := range []string{"session.yaml", "session.json"} {
+		t.Run(name, func(t *testing.T) {
+			cassettePath := filepath.Join(t.TempDir(), "cassettes", name)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/api/v1/auth/login":
+					_, _ = w.Write([]byte(`{"access_token":"issued-token"}`))
+				case "/api/v1/posts":
+					_, _ = w.Write([]byte(`{"data":[{"slug":"hello"}],"page":"` + r.URL.Query().Get("page") + `"}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"message":"Not found"}`))
+				}
+			}))
+
+			recorder, err := OpenCassette(cassettePath, CassetteRecord)
+			if err != nil {
+				t.Fatalf("failed to open cassette for recording: %v", err)
+			}
+
+			client := New(server.URL, "secret-token", WithCassette(recorder))
+			ctx := context.Background()
+			if _, err := client.Post(ctx, "/api/v1/auth/login", map[string]any{"email": "a@example.com", "password": "hunter2"}); err != nil {
+				t.Fatalf("login failed: %v", err)
+			}
+			if _, err := client.Get(ctx, "/api/v1/posts?page=1"); err != nil {
+				t.Fatalf("list failed: %v", err)
+			}
+			if _, err := client.Get(ctx, "/api/v1/posts/missing"); err == nil {
+				t.Fatal("expected 404 error")
+			}
+			server.Close()
+
+			data, err := os.ReadFile(cassettePath)
+			if err != nil {
+				t.Fatalf("failed to read cassette: %v", err)
+			}
+			for _, secret := range []string{"secret-token", "hunter2", "issued-token"} {
+				if strings.Contains(string(data), secret) {
+					t.Fatalf("expected %q to be scrubbed from cassette:\n%s", secret, data)
+				}
+			}
+
+			player, err := OpenCassette(cassettePath, CassetteReplay)
+			if err != nil {
+				t.Fatalf("failed to open cassette for replay: %v", err)
+			}
+
+			client = New("http://replay.invalid", "other-token", WithCassette(player))
+			response, err := client.Get(ctx, "/api/v1/posts?page=1")
+			if err != nil {
+				t.Fatalf("expected replayed response, got %v", err)
+			}
+			if response["page"] != "1" {
+				t.Fatalf("unexpected replayed response %#v", response)
+			}
+
+			_, err = client.Get(ctx, "/api/v1/posts/missing")
+			apiErr := &APIError{}
+			if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound {
+				t.Fatalf("expected replayed 404, got %v", err)
+			}
+
+			if _, err := client.Post(ctx, "/api/v1/auth/login", map[string]any{"email": "a@example.com", "password": "different"}); err != nil {
+				t.Fatalf("expected login with scrubbed password to match, got %v", err)
+			}
+
+			if _, err := client.Get(ctx, "/api/v1/posts?page=1"); !errors.Is(err, ErrCassetteMismatch) {
+				t.Fatalf("expected used interaction not to be replayed twice, got %v", err)
+			}
+			if _, err := client.Get(ctx, "/api/v1/posts?page=2"); !errors.Is(err, ErrCassetteMismatch) {
+				t.Fatalf("expected unmatched request to fail, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOpenCassetteRejectsUnknownMode(t *testing.T) {
+	if _, err := OpenCassette(filepath.Join(t.TempDir(), "c.yaml"), "rewind"); err == nil {
+		t.Fatal("expected unknown mode error")
+	}
+	if _, err := OpenCassette(filepath.Join(t.TempDir(), "missing.yaml"), CassetteReplay); err == nil {
+		t.Fatal("expected missing cassette error")
+	}
+}
@@ -2,71 +2,136 @@ package httpclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 )
 
-type APIError struct {
-	Status int
-	Body   map[string]any
-	Raw    string
+type Client struct {
+	baseURL     string
+	accessToken string
+	httpClient  *http.Client
+	retry       RetryPolicy
+	timeouts    Timeouts
+	logger      io.Writer
+	curl        io.Writer
+	cassette    *Cassette
+	sleep       func(context.Context, time.Duration) error
+}
+
+// Timeouts bound a single attempt of a request. Zero disables a timeout.
+type Timeouts struct {
+	// Request bounds JSON requests, including reading the response.
+	Request time.Duration
+	// Connect bounds dialing and the TLS handshake.
+	Connect time.Duration
+	// Upload replaces Request for multipart file uploads.
+	Upload time.Duration
+}
+
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		Request: 30 * time.Second,
+		Connect: 10 * time.Second,
+		Upload:  5 * time.Minute,
+	}
 }
 
-func (e *APIError) Error() string {
-	if message, ok := e.Body["message"].(string); ok && message != "" {
-		return message
+type Option func(*Client)
+
+// WithRetryPolicy replaces the default retry policy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
 	}
+}
 
-	if e.Raw != "" {
-		return e.Raw
+func WithTimeouts(timeouts Timeouts) Option {
+	return func(c *Client) {
+		c.timeouts = timeouts
 	}
+}
 
-	return fmt.Sprintf("request failed with status %d", e.Status)
+// WithLogger enables verbose tracing of requests, responses and retries to
+// w. Secrets are redacted.
+func WithLogger(w io.Writer) Option {
+	return func(c *Client) {
+		c.logger = w
+	}
 }
 
-type Client struct {
-	baseURL     string
-	accessToken string
-	httpClient  *http.Client
+// WithCurl writes an equivalent curl command for every request to w, with the
+// bearer token masked.
+func WithCurl(w io.Writer) Option {
+	return func(c *Client) {
+		c.curl = w
+	}
 }
 
-func New(baseURL string, accessToken string) *Client {
-	return &Client{
+func New(baseURL string, accessToken string, options ...Option) *Client {
+	client := &Client{
 		baseURL:     strings.TrimRight(baseURL, "/"),
 		accessToken: accessToken,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		retry:       DefaultRetryPolicy(),
+		timeouts:    DefaultTimeouts(),
+		sleep:       sleepContext,
 	}
+
+	for _, option := range options {
+		option(client)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   client.timeouts.Connect,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = client.timeouts.Connect
+	client.httpClient = &http.Client{Transport: transport}
+	if client.cassette != nil {
+		client.httpClient.Transport = client.cassette.Transport(transport)
+	}
+
+	return client
+}
+
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+func (c *Client) Get(ctx context.Context, p string) (map[string]any, error) {
+	return c.do(ctx, http.MethodGet, p, nil)
 }
 
-func (c *Client) Get(p string) (map[string]any, error) {
-	return c.do(http.MethodGet, p, nil)
+func (c *Client) Post(ctx context.Context, p string, payload any) (map[string]any, error) {
+	return c.do(ctx, http.MethodPost, p, payload)
 }
 
-func (c *Client) Post(p string, payload any) (map[string]any, error) {
-	return c.do(http.MethodPost, p, payload)
+func (c *Client) Put(ctx context.Context, p string, payload any) (map[string]any, error) {
+	return c.do(ctx, http.MethodPut, p, payload)
 }
 
-func (c *Client) Put(p string, payload any) (map[string]any, error) {
-	return c.do(http.MethodPut, p, payload)
+func (c *Client) Patch(ctx context.Context, p string, payload any) (map[string]any, error) {
+	return c.do(ctx, http.MethodPatch, p, payload)
 }
 
-func (c *Client) Delete(p string) (map[string]any, error) {
-	return c.do(http.MethodDelete, p, nil)
+func (c *Client) Delete(ctx context.Context, p string) (map[string]any, error) {
+	return c.do(ctx, http.MethodDelete, p, nil)
 }
 
-func (c *Client) PostMultipartFile(p string, fileField string, filePath string, fields map[string]string) (map[string]any, error) {
+func (c *Client) PostMultipartFile(ctx context.Context, p string, fileField string, filePath string, fields map[string]string) (map[string]any, error) {
 	var body bytes.Buffer
 	writer := multipart.NewWriter(&body)
 
@@ -95,11 +160,25 @@ func (c *Client) PostMultipartFile(p string, fileField string, filePath string,
 		return nil, err
 	}
 
-	return c.doRaw(http.MethodPost, p, &body, writer.FormDataContentType())
+	form := make([]string, 0, len(fields)+1)
+	for key, value := range fields {
+		form = append(form, key+"="+value)
+	}
+	sort.Strings(form)
+	form = append(form, fileField+"=@"+filePath)
+
+	return c.doRaw(ctx, request{
+		method:      http.MethodPost,
+		path:        p,
+		body:        body.Bytes(),
+		contentType: writer.FormDataContentType(),
+		timeout:     c.timeouts.Upload,
+		form:        form,
+	})
 }
 
-func (c *Client) do(method string, p string, payload any) (map[string]any, error) {
-	var bodyReader io.Reader
+func (c *Client) do(ctx context.Context, method string, p string, payload any) (map[string]any, error) {
+	var body []byte
 	contentType := ""
 	if payload != nil {
 		payloadBytes, err := json.Marshal(payload)
@@ -107,14 +186,53 @@ func (c *Client) do(method string, p string, payload any) (map[string]any, error
 			return nil, err
 		}
 
-		bodyReader = bytes.NewReader(payloadBytes)
+		body = payloadBytes
 		contentType = "application/json"
 	}
 
-	return c.doRaw(method, p, bodyReader, contentType)
+	return c.doRaw(ctx, request{
+		method:      method,
+		path:        p,
+		body:        body,
+		contentType: contentType,
+		timeout:     c.timeouts.Request,
+	})
+}
+
+// request describes one API call. The body is kept as bytes so every attempt
+// can resend it, and timeout bounds each attempt separately.
+type request struct {
+	method      string
+	path        string
+	body        []byte
+	contentType string
+	timeout     time.Duration
+	// form lists multipart fields as curl -F arguments, for --print-curl.
+	form []string
+}
+
+type headersKey struct{}
+
+// WithHeader returns a copy of ctx that makes every request sent with it
+// carry the header, for per-call headers such as preconditions.
+func WithHeader(ctx context.Context, name string, value string) context.Context {
+	headers := http.Header{}
+	if existing, ok := ctx.Value(headersKey{}).(http.Header); ok {
+		headers = existing.Clone()
+	}
+	headers.Set(name, value)
+
+	return context.WithValue(ctx, headersKey{}, headers)
 }
 
-func (c *Client) doRaw(method string, p string, bodyReader io.Reader, contentType string) (map[string]any, error) {
+func contextHeaders(ctx context.Context) http.Header {
+	headers, _ := ctx.Value(headersKey{}).(http.Header)
+
+	return headers
+}
+
+// doRaw sends the request, retrying according to the client's retry policy.
+func (c *Client) doRaw(ctx context.Context, r request) (map[string]any, error) {
 	if c.baseURL == "" {
 		return nil, errors.New("base URL is required")
 	}
@@ -124,7 +242,7 @@ func (c *Client) doRaw(method string, p string, bodyReader io.Reader, contentTyp
 		return nil, err
 	}
 
-	endpointURL, err := url.Parse(p)
+	endpointURL, err := url.Parse(r.path)
 	if err != nil {
 		return nil, err
 	}
@@ -132,44 +250,115 @@ func (c *Client) doRaw(method string, p string, bodyReader io.Reader, contentTyp
 	baseURL.Path = path.Join(baseURL.Path, endpointURL.Path)
 	baseURL.RawQuery = endpointURL.RawQuery
 
-	req, err := http.NewRequest(method, baseURL.String(), bodyReader)
+	endpoint := baseURL.String()
+	maxAttempts := c.retry.attempts(r.method)
+
+	c.printCurl(endpoint, r, contextHeaders(ctx))
+
+	for attempt := 1; ; attempt++ {
+		result, resp, err := c.send(ctx, endpoint, r)
+		if attempt >= maxAttempts || ctx.Err() != nil || !c.retry.shouldRetry(resp, err) {
+			return result, err
+		}
+
+		wait, ok := c.retry.delay(attempt, resp)
+		if !ok {
+			return result, err
+		}
+
+		c.logRetry(r.method, endpoint, resp, err, attempt+1, maxAttempts, wait)
+		if err := c.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, endpoint string, r request) (map[string]any, *http.Response, error) {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	var bodyReader io.Reader
+	if r.body != nil {
+		bodyReader = bytes.NewReader(r.body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, endpoint, bodyReader)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	req.Header.Set("Accept", "application/json")
-	if contentType != "" {
-		req.Header.Set("Content-Type", contentType)
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
 	}
 	if c.accessToken != "" {
 		req.Header.Set("Authorization", "Bearer "+c.accessToken)
 	}
+	for name, values := range contextHeaders(ctx) {
+		req.Header[name] = values
+	}
+
+	c.traceRequest(req, r)
+	started := time.Now()
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		c.traceError(err, time.Since(started))
+
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	respBody, err := io.ReadAll(resp.Body)
+	c.traceResponse(resp, respBody, time.Since(started))
 	if err != nil {
-		return nil, err
+		return nil, resp, err
 	}
 
 	result := map[string]any{}
-	if len(body) > 0 {
-		if err := json.Unmarshal(body, &result); err != nil {
+	if len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, &result); err != nil {
 			if resp.StatusCode >= 400 {
-				return nil, &APIError{Status: resp.StatusCode, Raw: string(body)}
+				return nil, resp, newAPIError(resp, map[string]any{}, respBody)
 			}
 
-			return nil, fmt.Errorf("failed to decode response: %w", err)
+			return nil, resp, fmt.Errorf("failed to decode response: %w", err)
 		}
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, &APIError{Status: resp.StatusCode, Body: result, Raw: string(body)}
+		return nil, resp, newAPIError(resp, result, respBody)
+	}
+
+	return result, resp, nil
+}
+
+func (c *Client) logRetry(method string, endpoint string, resp *http.Response, err error, attempt int, maxAttempts int, wait time.Duration) {
+	if c.logger == nil {
+		return
+	}
+
+	reason := ""
+	if resp != nil {
+		reason = resp.Status
+	} else {
+		reason = err.Error()
 	}
 
-	return result, nil
+	fmt.Fprintf(c.logger, "retrying %s %s after %s (attempt %d/%d in %s)\n", method, endpoint, reason, attempt, maxAttempts, wait)
+}
+
+func sleepContext(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
@@ -1,6 +1,7 @@
 package httpclient
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -25,7 +26,7 @@ func TestGetPreservesQueryString(t *testing.T) {
 	defer server.Close()
 
 	client := New(server.URL, "")
-	response, err := client.Get("/api/v1/posts?per_page=5&search=cli-test")
+	response, err := client.Get(context.Background(), "/api/v1/posts?per_page=5&search=cli-test")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -80,7 +81,7 @@ func TestPostMultipartFileSendsFileAndFields(t *testing.T) {
 	defer server.Close()
 
 	client := New(server.URL, "token")
-	response, err := client.PostMultipartFile("/api/v1/media", "file", filePath, map[string]string{
+	response, err := client.PostMultipartFile(context.Background(), "/api/v1/media", "file", filePath, map[string]string{
 		"alt_text[vi]": "Anh",
 		"alt_text[en]": "Image",
 	})
@@ -0,0 +1,72 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Download streams the file at link to w. link may be an absolute URL or a
+// path on the API's host; the access token is only sent to the API's own
+// host. Downloads use the upload timeout and are not retried.
+func (c *Client) Download(ctx context.Context, link string, w io.Writer) error {
+	target, err := c.resolveLink(link)
+	if err != nil {
+		return err
+	}
+
+	if c.timeouts.Upload > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeouts.Upload)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	if base, err := url.Parse(c.baseURL); err == nil && base.Host == target.Host && c.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+
+	c.traceRequest(req, request{method: http.MethodGet})
+	started := time.Now()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.traceError(err, time.Since(started))
+
+		return err
+	}
+	defer resp.Body.Close()
+
+	c.traceResponse(resp, nil, time.Since(started))
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxTracedBody))
+
+		return &APIError{Status: resp.StatusCode, Code: statusCode(resp.StatusCode), Raw: string(body)}
+	}
+
+	_, err = io.Copy(w, resp.Body)
+
+	return err
+}
+
+// resolveLink resolves link the way a browser would on the API's site, so
+// /storage/a.png is taken from the host root.
+func (c *Client) resolveLink(link string) (*url.URL, error) {
+	target, err := url.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return base.ResolveReference(target), nil
+}
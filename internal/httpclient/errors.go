@@ -0,0 +1,140 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors matched by APIError.Is, so callers can branch with
+// errors.Is(err, httpclient.ErrNotFound) instead of comparing statuses.
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
+// APIError is a response with a status of 400 or more.
+type APIError struct {
+	Status int
+	// Code is the response's error_code, or one derived from Status when the
+	// API does not send one.
+	Code    string
+	Message string
+	// Fields holds Laravel validation messages by field, from the errors
+	// object of a 422 response.
+	Fields map[string][]string
+	// RetryAfter is the wait requested by a Retry-After header, if any.
+	RetryAfter time.Duration
+	Body       map[string]any
+	Raw        string
+}
+
+func newAPIError(resp *http.Response, body map[string]any, raw []byte) *APIError {
+	apiErr := &APIError{
+		Status: resp.StatusCode,
+		Body:   body,
+		Raw:    string(raw),
+	}
+
+	apiErr.Message, _ = body["message"].(string)
+	apiErr.Code, _ = body["error_code"].(string)
+	if apiErr.Code == "" {
+		apiErr.Code = statusCode(resp.StatusCode)
+	}
+
+	if fields, ok := body["errors"].(map[string]any); ok {
+		apiErr.Fields = map[string][]string{}
+		for field, value := range fields {
+			apiErr.Fields[field] = fieldMessages(value)
+		}
+	}
+
+	if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		apiErr.RetryAfter = wait
+	}
+
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+
+	if e.Raw != "" {
+		return e.Raw
+	}
+
+	return fmt.Sprintf("request failed with status %d", e.Status)
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.Status == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.Status == http.StatusForbidden
+	case ErrNotFound:
+		return e.Status == http.StatusNotFound
+	case ErrConflict:
+		return e.Status == http.StatusConflict || e.Status == http.StatusPreconditionFailed
+	case ErrValidation:
+		return e.Status == http.StatusUnprocessableEntity
+	case ErrRateLimited:
+		return e.Status == http.StatusTooManyRequests
+	default:
+		return false
+	}
+}
+
+func statusCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "bad_request"
+	case http.StatusUnauthorized:
+		return "unauthenticated"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return "conflict"
+	case http.StatusUnprocessableEntity:
+		return "validation_failed"
+	case http.StatusTooManyRequests:
+		return "rate_limited"
+	default:
+		if status >= 500 {
+			return "server_error"
+		}
+
+		return "api_error"
+	}
+}
+
+// fieldMessages accepts Laravel's list of messages per field as well as a
+// single message string.
+func fieldMessages(value any) []string {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}
+	case []any:
+		messages := make([]string, 0, len(typed))
+		for _, item := range typed {
+			if message, ok := item.(string); ok {
+				messages = append(messages, message)
+			} else {
+				messages = append(messages, fmt.Sprint(item))
+			}
+		}
+
+		return messages
+	default:
+		return []string{fmt.Sprint(value)}
+	}
+}
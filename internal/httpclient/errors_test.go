@@ -0,0 +1,96 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidationErrorHasFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message":"The slug has already been taken. (and 1 more error)","errors":{"slug":["The slug has already been taken."],"title.vi":"The title.vi field is required."}}`))
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL, "").Post(context.Background(), "/api/v1/posts", map[string]any{"slug": "a"})
+
+	apiErr := &APIError{}
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if !errors.Is(err, ErrValidation) || errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected only ErrValidation to match, got %v", err)
+	}
+	if apiErr.Code != "validation_failed" {
+		t.Fatalf("expected derived code validation_failed, got %q", apiErr.Code)
+	}
+
+	expected := map[string][]string{
+		"slug":     {"The slug has already been taken."},
+		"title.vi": {"The title.vi field is required."},
+	}
+	if !reflect.DeepEqual(apiErr.Fields, expected) {
+		t.Fatalf("expected fields %#v, got %#v", expected, apiErr.Fields)
+	}
+}
+
+func TestAPIErrorStatusMapping(t *testing.T) {
+	tests := []struct {
+		status int
+		target error
+		code   string
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized, "unauthenticated"},
+		{http.StatusForbidden, ErrForbidden, "forbidden"},
+		{http.StatusNotFound, ErrNotFound, "not_found"},
+		{http.StatusConflict, ErrConflict, "conflict"},
+		{http.StatusPreconditionFailed, ErrConflict, "conflict"},
+		{http.StatusTooManyRequests, ErrRateLimited, "rate_limited"},
+	}
+
+	for _, test := range tests {
+		resp := &http.Response{StatusCode: test.status, Header: http.Header{"Retry-After": {"7"}}}
+		apiErr := newAPIError(resp, map[string]any{}, []byte("{}"))
+
+		if !errors.Is(apiErr, test.target) {
+			t.Fatalf("expected status %d to match %v", test.status, test.target)
+		}
+		if apiErr.Code != test.code {
+			t.Fatalf("expected status %d to have code %q, got %q", test.status, test.code, apiErr.Code)
+		}
+		if apiErr.RetryAfter != 7*time.Second {
+			t.Fatalf("expected Retry-After to be parsed, got %s", apiErr.RetryAfter)
+		}
+	}
+
+	apiErr := newAPIError(&http.Response{StatusCode: http.StatusNotFound}, map[string]any{"error_code": "post_not_found"}, nil)
+	if apiErr.Code != "post_not_found" {
+		t.Fatalf("expected error_code from body, got %q", apiErr.Code)
+	}
+}
+
+func TestHTMLErrorPageIsTypedAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("<html><body>Down for maintenance</body></html>"))
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL, "", WithRetryPolicy(RetryPolicy{MaxAttempts: 1})).Get(context.Background(), "/api/v1/posts")
+
+	apiErr := &APIError{}
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.Code != "server_error" || apiErr.RetryAfter != 30*time.Second || apiErr.Raw == "" {
+		t.Fatalf("expected code, Retry-After and raw body, got %+v", apiErr)
+	}
+}
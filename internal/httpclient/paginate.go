@@ -0,0 +1,166 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Pager walks a Laravel-style paginated listing one page at a time. The next
+// page is taken from meta.current_page/meta.last_page when present, and from
+// links.next otherwise. Responses without either are treated as a single
+// page. A server that answers a page request with an earlier page is an
+// error, since following it would request the same page forever.
+type Pager struct {
+	client *Client
+	next   string
+	page   map[string]any
+	err    error
+}
+
+// Pages returns a Pager that starts at p. Nothing is requested until the
+// first call to Next.
+func (c *Client) Pages(p string) *Pager {
+	return &Pager{client: c, next: p}
+}
+
+// Next fetches the following page and reports whether one was fetched. It
+// returns false once the last page has been read or a request fails; check
+// Err to tell the two apart.
+func (p *Pager) Next(ctx context.Context) bool {
+	if p.err != nil || p.next == "" {
+		return false
+	}
+
+	page, err := p.client.Get(ctx, p.next)
+	if err != nil {
+		p.err = err
+		p.page = nil
+
+		return false
+	}
+
+	current := p.next
+	if err := checkPage(current, page); err != nil {
+		p.err = err
+		p.page = nil
+
+		return false
+	}
+
+	p.page = page
+	p.next = p.client.nextPage(current, page)
+
+	return true
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *Pager) Page() map[string]any {
+	return p.page
+}
+
+// Items returns the data array of the current page.
+func (p *Pager) Items() []any {
+	return PageItems(p.page)
+}
+
+func (p *Pager) Err() error {
+	return p.err
+}
+
+// PageItems returns the data array of a listing response, or nil when the
+// response has none.
+func PageItems(page map[string]any) []any {
+	items, _ := page["data"].([]any)
+
+	return items
+}
+
+func (c *Client) nextPage(current string, page map[string]any) string {
+	if meta, ok := page["meta"].(map[string]any); ok {
+		currentPage, hasCurrent := number(meta["current_page"])
+		lastPage, hasLast := number(meta["last_page"])
+		if hasCurrent && hasLast {
+			if currentPage >= lastPage {
+				return ""
+			}
+
+			return withPage(current, currentPage+1)
+		}
+	}
+
+	links, _ := page["links"].(map[string]any)
+	next, _ := links["next"].(string)
+	if next == "" {
+		return ""
+	}
+
+	return c.relativePath(next)
+}
+
+// checkPage fails when the request at p asked for a page that the
+// response's meta.current_page does not reach.
+func checkPage(p string, page map[string]any) error {
+	endpoint, err := url.Parse(p)
+	if err != nil {
+		return nil
+	}
+	requested, ok := number(endpoint.Query().Get("page"))
+	if !ok {
+		return nil
+	}
+
+	meta, _ := page["meta"].(map[string]any)
+	currentPage, ok := number(meta["current_page"])
+	if !ok || currentPage >= requested {
+		return nil
+	}
+
+	return fmt.Errorf("requested page %d of %s but the server returned page %d", requested, endpoint.Path, currentPage)
+}
+
+// relativePath turns an absolute next link into a path below the client's
+// base URL, so the request goes through the usual endpoint handling.
+func (c *Client) relativePath(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	p := parsed.RequestURI()
+	if base, err := url.Parse(c.baseURL); err == nil {
+		if prefix := strings.TrimRight(base.Path, "/"); prefix != "" && strings.HasPrefix(p, prefix+"/") {
+			p = strings.TrimPrefix(p, prefix)
+		}
+	}
+
+	return p
+}
+
+func withPage(p string, page int) string {
+	endpoint, err := url.Parse(p)
+	if err != nil {
+		return ""
+	}
+
+	query := endpoint.Query()
+	query.Set("page", strconv.Itoa(page))
+	endpoint.RawQuery = query.Encode()
+
+	return endpoint.String()
+}
+
+func number(value any) (int, bool) {
+	switch typed := value.(type) {
+	case float64:
+		return int(typed), true
+	case string:
+		parsed, err := strconv.Atoi(typed)
+
+		return parsed, err == nil
+	default:
+		return 0, false
+	}
+}
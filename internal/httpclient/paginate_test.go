@@ -0,0 +1,133 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPagesFollowsMetaLastPage(t *testing.T) {
+	var requested []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RawQuery)
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []any{map[string]any{"id": page}},
+			"meta": map[string]any{"current_page": page, "last_page": 3},
+		})
+	}))
+	defer server.Close()
+
+	pager := New(server.URL, "").Pages("/api/v1/posts?per_page=1&status=draft")
+
+	var ids []any
+	for pager.Next(context.Background()) {
+		for _, item := range pager.Items() {
+			ids = append(ids, item.(map[string]any)["id"])
+		}
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(ids) != 3 || ids[0] != float64(1) || ids[2] != float64(3) {
+		t.Fatalf("expected ids 1..3, got %#v", ids)
+	}
+
+	expected := []string{"per_page=1&status=draft", "page=2&per_page=1&status=draft", "page=3&per_page=1&status=draft"}
+	for i, query := range expected {
+		if requested[i] != query {
+			t.Fatalf("expected request %d query %q, got %q", i, query, requested[i])
+		}
+	}
+}
+
+func TestPagesStopsWhenServerIgnoresPage(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []any{map[string]any{"id": 1}},
+			"meta": map[string]any{"current_page": 1, "last_page": 3},
+		})
+	}))
+	defer server.Close()
+
+	pager := New(server.URL, "").Pages("/api/v1/posts")
+
+	pages := 0
+	for pager.Next(context.Background()) {
+		pages++
+	}
+	if pages != 1 || requests != 2 {
+		t.Fatalf("expected to stop after the repeated page, got %d pages from %d requests", pages, requests)
+	}
+	if err := pager.Err(); err == nil || !strings.Contains(err.Error(), "requested page 2") {
+		t.Fatalf("expected an error for the ignored page, got %v", err)
+	}
+}
+
+func TestPagesFollowsLinksNextBelowBasePath(t *testing.T) {
+	var requested []string
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RequestURI())
+
+		response := map[string]any{"data": []any{map[string]any{"id": len(requested)}}}
+		if len(requested) == 1 {
+			response["links"] = map[string]any{"next": server.URL + "/geda/api/v1/tags?cursor=abc"}
+		} else {
+			response["links"] = map[string]any{"next": nil}
+		}
+
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	pager := New(server.URL+"/geda", "").Pages("/api/v1/tags")
+
+	pages := 0
+	for pager.Next(context.Background()) {
+		pages++
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if pages != 2 {
+		t.Fatalf("expected 2 pages, got %d", pages)
+	}
+	if requested[1] != "/geda/api/v1/tags?cursor=abc" {
+		t.Fatalf("expected next link to be followed, got %s", requested[1])
+	}
+}
+
+func TestPagesStopsOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]any{"message": "Unauthenticated."})
+	}))
+	defer server.Close()
+
+	pager := New(server.URL, "").Pages("/api/v1/posts")
+	if pager.Next(context.Background()) {
+		t.Fatal("expected Next to fail")
+	}
+
+	apiErr, ok := pager.Err().(*APIError)
+	if !ok || apiErr.Status != http.StatusUnauthorized {
+		t.Fatalf("expected 401 APIError, got %#v", pager.Err())
+	}
+}
@@ -0,0 +1,118 @@
+package httpclient
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. Connection errors
+// and 408, 429, 502, 503 and 504 responses are retried; other responses are
+// returned as is.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 1 disable retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry; it doubles on every
+	// following attempt.
+	BaseDelay time.Duration
+	// MaxWait caps a single wait. A Retry-After longer than MaxWait stops
+	// retrying instead of waiting.
+	MaxWait time.Duration
+	// RetryPost allows retrying POST, which is not idempotent.
+	RetryPost bool
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxWait:     30 * time.Second,
+	}
+}
+
+func (p RetryPolicy) attempts(method string) int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return p.MaxAttempts
+	case http.MethodPost:
+		if p.RetryPost {
+			return p.MaxAttempts
+		}
+	}
+
+	return 1
+}
+
+func (p RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if resp == nil {
+		return err != nil && !errors.Is(err, ErrCassetteMismatch)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// delay returns how long to wait before the attempt following attempt, and
+// false when the server asks for a longer wait than MaxWait allows.
+func (p RetryPolicy) delay(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if p.MaxWait > 0 && wait > p.MaxWait {
+				return 0, false
+			}
+
+			return wait, true
+		}
+	}
+
+	wait := p.BaseDelay << (attempt - 1)
+	if p.MaxWait > 0 && (wait > p.MaxWait || wait <= 0) {
+		wait = p.MaxWait
+	}
+
+	// Equal jitter: wait somewhere between half and the full backoff so that
+	// parallel clients do not retry in lockstep.
+	if half := wait / 2; half > 0 {
+		wait = half + rand.N(half+1)
+	}
+
+	return wait, true
+}
+
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		wait := at.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+
+		return wait, true
+	}
+
+	return 0, false
+}
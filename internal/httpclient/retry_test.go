@@ -0,0 +1,214 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetRetriesOnServiceUnavailable(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := New(server.URL, "", WithLogger(&logs))
+	waits := stubSleep(client)
+
+	response, err := client.Get(context.Background(), "/api/v1/posts")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if response["ok"] != true {
+		t.Fatalf("unexpected response %#v", response)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls.Load())
+	}
+	if len(*waits) != 2 {
+		t.Fatalf("expected 2 waits, got %v", *waits)
+	}
+	if (*waits)[1] < (*waits)[0] {
+		t.Fatalf("expected backoff to grow, got %v", *waits)
+	}
+	if strings.Count(logs.String(), "retrying GET") != 2 {
+		t.Fatalf("expected retries to be logged, got %q", logs.String())
+	}
+}
+
+func TestGetGivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "", WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+	stubSleep(client)
+
+	_, err := client.Get(context.Background(), "/api/v1/posts")
+	apiErr := &APIError{}
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadGateway {
+		t.Fatalf("expected 502 APIError, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestPostIsRetriedOnlyWhenEnabled(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "")
+	stubSleep(client)
+
+	if _, err := client.Post(context.Background(), "/api/v1/posts", map[string]any{"slug": "a"}); err == nil {
+		t.Fatal("expected POST to fail without retry")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 attempt, got %d", calls.Load())
+	}
+
+	calls.Store(0)
+	policy := DefaultRetryPolicy()
+	policy.RetryPost = true
+	client = New(server.URL, "", WithRetryPolicy(policy))
+	stubSleep(client)
+
+	if _, err := client.Post(context.Background(), "/api/v1/posts", map[string]any{"slug": "a"}); err != nil {
+		t.Fatalf("expected POST to succeed on retry, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestRetryResendsRequestBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buffer bytes.Buffer
+		_, _ = buffer.ReadFrom(r.Body)
+		bodies = append(bodies, buffer.String())
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "")
+	stubSleep(client)
+
+	if _, err := client.Put(context.Background(), "/api/v1/posts/a", map[string]any{"status": "draft"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[1] == "" {
+		t.Fatalf("expected identical bodies on retry, got %q", bodies)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "")
+	waits := stubSleep(client)
+
+	if _, err := client.Get(context.Background(), "/api/v1/posts"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 7*time.Second {
+		t.Fatalf("expected a single 7s wait, got %v", *waits)
+	}
+
+	calls.Store(0)
+	policy := DefaultRetryPolicy()
+	policy.MaxWait = 5 * time.Second
+	client = New(server.URL, "", WithRetryPolicy(policy))
+	waits = stubSleep(client)
+
+	if _, err := client.Get(context.Background(), "/api/v1/posts"); err == nil {
+		t.Fatal("expected Retry-After beyond max wait to stop retrying")
+	}
+	if len(*waits) != 0 || calls.Load() != 1 {
+		t.Fatalf("expected no retry, got waits=%v calls=%d", *waits, calls.Load())
+	}
+}
+
+func TestRetryOnConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serverURL := server.URL
+	server.Close()
+
+	client := New(serverURL, "", WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	waits := stubSleep(client)
+
+	if _, err := client.Get(context.Background(), "/api/v1/health"); err == nil {
+		t.Fatal("expected connection error")
+	}
+	if len(*waits) != 2 {
+		t.Fatalf("expected 2 retries, got %v", *waits)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+
+	wait, ok := parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now)
+	if !ok || wait != 90*time.Second {
+		t.Fatalf("expected 90s from HTTP date, got %v (%v)", wait, ok)
+	}
+
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Fatal("expected invalid Retry-After to be ignored")
+	}
+}
+
+func stubSleep(client *Client) *[]time.Duration {
+	waits := []time.Duration{}
+	client.sleep = func(_ context.Context, wait time.Duration) error {
+		waits = append(waits, wait)
+
+		return nil
+	}
+
+	return &waits
+}
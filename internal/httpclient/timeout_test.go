@@ -0,0 +1,80 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGetStopsWhenContextIsCanceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	client := New(server.URL, "")
+	started := time.Now()
+	_, err := client.Get(ctx, "/api/v1/posts")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if time.Since(started) > 5*time.Second {
+		t.Fatal("expected cancellation to return promptly")
+	}
+}
+
+func TestRequestTimeoutAppliesPerAttempt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "",
+		WithTimeouts(Timeouts{Request: 20 * time.Millisecond}),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+	)
+	waits := stubSleep(client)
+
+	_, err := client.Get(context.Background(), "/api/v1/posts")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if len(*waits) != 1 {
+		t.Fatalf("expected the timed out attempt to be retried once, got %v", *waits)
+	}
+}
+
+func TestUploadUsesUploadTimeout(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "image.png")
+	if err := os.WriteFile(filePath, []byte("test-image"), 0o600); err != nil {
+		t.Fatalf("failed to write temp image file: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"message":"ok"}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "", WithTimeouts(Timeouts{
+		Request: 10 * time.Millisecond,
+		Upload:  5 * time.Second,
+	}))
+
+	if _, err := client.PostMultipartFile(context.Background(), "/api/v1/media", "file", filePath, nil); err != nil {
+		t.Fatalf("expected upload to use the longer upload timeout, got %v", err)
+	}
+}
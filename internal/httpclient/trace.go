@@ -0,0 +1,188 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxTracedBody = 2048
+	redacted      = "****"
+)
+
+// sensitiveFields are JSON keys whose values never appear in traces or
+// curl commands.
+var sensitiveFields = map[string]bool{
+	"password":              true,
+	"password_confirmation": true,
+	"current_password":      true,
+	"access_token":          true,
+	"refresh_token":         true,
+	"token":                 true,
+	"otp":                   true,
+	"recovery_code":         true,
+}
+
+func (c *Client) traceRequest(req *http.Request, r request) {
+	if c.logger == nil {
+		return
+	}
+
+	fmt.Fprintf(c.logger, "> %s %s\n", req.Method, req.URL.String())
+	writeHeaders(c.logger, "> ", req.Header)
+	if len(r.body) > 0 {
+		fmt.Fprintf(c.logger, "> \n%s\n", describeBody(r.body, r.contentType))
+	}
+}
+
+func (c *Client) traceResponse(resp *http.Response, body []byte, elapsed time.Duration) {
+	if c.logger == nil {
+		return
+	}
+
+	fmt.Fprintf(c.logger, "< %s %s (%s)\n", resp.Proto, resp.Status, elapsed.Round(time.Millisecond))
+	writeHeaders(c.logger, "< ", resp.Header)
+	if len(body) > 0 {
+		fmt.Fprintf(c.logger, "< \n%s\n", describeBody(body, resp.Header.Get("Content-Type")))
+	}
+}
+
+func (c *Client) traceError(err error, elapsed time.Duration) {
+	if c.logger == nil {
+		return
+	}
+
+	fmt.Fprintf(c.logger, "! request failed after %s: %v\n", elapsed.Round(time.Millisecond), err)
+}
+
+func (c *Client) printCurl(endpoint string, r request, headers http.Header) {
+	if c.curl == nil {
+		return
+	}
+
+	parts := []string{"curl", "-X", r.method, shellQuote(endpoint), "-H", shellQuote("Accept: application/json")}
+	if c.accessToken != "" {
+		parts = append(parts, "-H", shellQuote("Authorization: Bearer "+redacted))
+	}
+	for _, name := range sortedNames(headers) {
+		for _, value := range headers.Values(name) {
+			parts = append(parts, "-H", shellQuote(name+": "+value))
+		}
+	}
+
+	switch {
+	case len(r.form) > 0:
+		for _, field := range r.form {
+			parts = append(parts, "-F", shellQuote(field))
+		}
+	case len(r.body) > 0:
+		parts = append(parts,
+			"-H", shellQuote("Content-Type: "+r.contentType),
+			"--data-raw", shellQuote(string(redactBody(r.body))),
+		)
+	}
+
+	fmt.Fprintln(c.curl, strings.Join(parts, " "))
+}
+
+func writeHeaders(w io.Writer, prefix string, header http.Header) {
+	for _, name := range sortedNames(header) {
+		for _, value := range header.Values(name) {
+			if strings.EqualFold(name, "Authorization") || strings.EqualFold(name, "Cookie") || strings.EqualFold(name, "Set-Cookie") {
+				value = redactHeader(value)
+			}
+
+			fmt.Fprintf(w, "%s%s: %s\n", prefix, name, value)
+		}
+	}
+}
+
+func sortedNames(header http.Header) []string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func redactHeader(value string) string {
+	if scheme, _, ok := strings.Cut(value, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return scheme + " " + redacted
+	}
+
+	return redacted
+}
+
+// describeBody renders a body for tracing: JSON with secrets redacted,
+// multipart as a size summary, everything truncated to maxTracedBody.
+func describeBody(body []byte, contentType string) string {
+	if strings.HasPrefix(contentType, "multipart/") {
+		return fmt.Sprintf("[%s body, %d bytes]", strings.SplitN(contentType, ";", 2)[0], len(body))
+	}
+
+	text := string(redactBody(body))
+	if len(text) > maxTracedBody {
+		// Cut on a rune boundary so multibyte text stays valid UTF-8.
+		end := maxTracedBody
+		for end > 0 && !utf8.RuneStart(text[end]) {
+			end--
+		}
+
+		return fmt.Sprintf("%s... [truncated, %d bytes total]", text[:end], len(text))
+	}
+
+	return text
+}
+
+// redactBody masks sensitiveFields in a JSON body. Bodies that are not JSON
+// are returned unchanged.
+func redactBody(body []byte) []byte {
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return body
+	}
+
+	encoded, err := json.Marshal(redactValue(decoded))
+	if err != nil {
+		return body
+	}
+
+	return encoded
+}
+
+func redactValue(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, item := range typed {
+			if sensitiveFields[strings.ToLower(key)] && item != nil {
+				typed[key] = redacted
+
+				continue
+			}
+
+			typed[key] = redactValue(item)
+		}
+
+		return typed
+	case []any:
+		for i, item := range typed {
+			typed[i] = redactValue(item)
+		}
+
+		return typed
+	default:
+		return value
+	}
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
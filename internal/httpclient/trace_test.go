@@ -0,0 +1,113 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestVerboseTraceRedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"issued-token","user":{"email":"admin@example.com"}}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := New(server.URL, "secret-token", WithLogger(&logs))
+
+	if _, err := client.Post(context.Background(), "/api/v1/auth/login", map[string]any{
+		"email":    "admin@example.com",
+		"password": "hunter2",
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	trace := logs.String()
+	for _, expected := range []string{
+		"> POST " + server.URL + "/api/v1/auth/login",
+		"> Authorization: Bearer ****",
+		`"password":"****"`,
+		"< HTTP/1.1 200 OK",
+		`"access_token":"****"`,
+		"admin@example.com",
+	} {
+		if !strings.Contains(trace, expected) {
+			t.Fatalf("expected trace to contain %q, got:\n%s", expected, trace)
+		}
+	}
+	for _, secret := range []string{"secret-token", "hunter2", "issued-token"} {
+		if strings.Contains(trace, secret) {
+			t.Fatalf("expected %q to be redacted, got:\n%s", secret, trace)
+		}
+	}
+}
+
+func TestVerboseTraceTruncatesLongBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html>" + strings.Repeat("x", 5000) + "</html>"))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := New(server.URL, "", WithLogger(&logs))
+
+	if _, err := client.Get(context.Background(), "/api/v1/health"); err == nil {
+		t.Fatal("expected HTML response to fail decoding")
+	}
+	if !strings.Contains(logs.String(), "<html>xxx") || !strings.Contains(logs.String(), "[truncated, 5013 bytes total]") {
+		t.Fatalf("expected truncated HTML body in trace, got:\n%s", logs.String())
+	}
+}
+
+func TestTracedBodyTruncatesOnRuneBoundary(t *testing.T) {
+	// "ế" is three bytes, so the cut at maxTracedBody falls inside a rune.
+	body := []byte("x" + strings.Repeat("ế", maxTracedBody))
+
+	traced := describeBody(body, "text/plain")
+	if !utf8.ValidString(traced) {
+		t.Fatalf("expected valid UTF-8 after truncation, got %q", traced[maxTracedBody-4:maxTracedBody+4])
+	}
+	if !strings.HasPrefix(traced, "x"+strings.Repeat("ế", (maxTracedBody-1)/3)+"... [truncated") {
+		t.Fatalf("expected the body cut before the split rune, got %q", traced[maxTracedBody-8:])
+	}
+}
+
+func TestPrintCurl(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var curl bytes.Buffer
+	client := New(server.URL, "secret-token", WithCurl(&curl))
+
+	if _, err := client.Put(context.Background(), "/api/v1/settings/site_name", map[string]any{"value": "It's GEDA"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "curl -X PUT '" + server.URL + "/api/v1/settings/site_name' -H 'Accept: application/json' -H 'Authorization: Bearer ****' -H 'Content-Type: application/json' --data-raw '{\"value\":\"It'\\''s GEDA\"}'\n"
+	if curl.String() != expected {
+		t.Fatalf("unexpected curl command:\n%s\nexpected:\n%s", curl.String(), expected)
+	}
+
+	filePath := filepath.Join(t.TempDir(), "image.png")
+	if err := os.WriteFile(filePath, []byte("test-image"), 0o600); err != nil {
+		t.Fatalf("failed to write temp image file: %v", err)
+	}
+
+	curl.Reset()
+	if _, err := client.PostMultipartFile(context.Background(), "/api/v1/media", "file", filePath, map[string]string{"alt_text[en]": "Image"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(curl.String(), "-F 'alt_text[en]=Image' -F 'file=@"+filePath+"'") {
+		t.Fatalf("unexpected multipart curl command: %s", curl.String())
+	}
+}
@@ -0,0 +1,144 @@
+package importer
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// Image is a file on disk that one or more documents use as an image.
+type Image struct {
+	// Path is the file, resolved against the directory of the document.
+	Path string
+	// AltText holds the alt text of the first use in each locale.
+	AltText map[string]string
+}
+
+// LocalImages finds the images the documents refer to by a relative path,
+// in the body or as featured_image or og_image, sorted by path. Every file
+// must exist.
+func LocalImages(documents map[string]Document) ([]Image, error) {
+	images := map[string]*Image{}
+	add := func(document Document, locale string, reference string, alt string) error {
+		filePath, ok := localImagePath(document, reference)
+		if !ok {
+			return nil
+		}
+
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return fmt.Errorf("image %s in the %s markdown: %w", reference, locale, err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("image %s in the %s markdown is a directory", reference, locale)
+		}
+
+		image := images[filePath]
+		if image == nil {
+			image = &Image{Path: filePath, AltText: map[string]string{}}
+			images[filePath] = image
+		}
+		if _, ok := image.AltText[locale]; !ok && alt != "" {
+			image.AltText[locale] = alt
+		}
+
+		return nil
+	}
+
+	for locale, document := range documents {
+		for _, reference := range []string{document.FrontMatter.FeaturedImage, document.FrontMatter.OGImage} {
+			if err := add(document, locale, reference, ""); err != nil {
+				return nil, err
+			}
+		}
+
+		_, err := renderMarkdown(document.BodyMD, document.Options, nil, locale, func(image *ast.Image, source []byte) error {
+			return add(document, locale, string(image.Destination), plainText(image, source))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	paths := make([]string, 0, len(images))
+	for filePath := range images {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	result := make([]Image, 0, len(paths))
+	for _, filePath := range paths {
+		result = append(result, *images[filePath])
+	}
+
+	return result, nil
+}
+
+// ReplaceImages returns the documents with every local image replaced by
+// its URL in urls, keyed by Image.Path, and the body rendered again.
+func ReplaceImages(documents map[string]Document, urls map[string]string) (map[string]Document, error) {
+	replace := func(document Document, reference string) string {
+		if filePath, ok := localImagePath(document, reference); ok && urls[filePath] != "" {
+			return urls[filePath]
+		}
+
+		return reference
+	}
+
+	result := make(map[string]Document, len(documents))
+	for locale, document := range documents {
+		document.FrontMatter.FeaturedImage = replace(document, document.FrontMatter.FeaturedImage)
+		document.FrontMatter.OGImage = replace(document, document.FrontMatter.OGImage)
+
+		bodyHTML, err := renderMarkdown(document.BodyMD, document.Options, document.Shortcodes, locale, func(image *ast.Image, _ []byte) error {
+			image.Destination = []byte(replace(document, string(image.Destination)))
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		document.BodyHTML = bodyHTML
+
+		result[locale] = document
+	}
+
+	return result, nil
+}
+
+// localImagePath resolves reference when it is a relative path rather than
+// a URL, a site path such as /storage/a.png or an anchor.
+func localImagePath(document Document, reference string) (string, bool) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" || strings.HasPrefix(reference, "/") || strings.HasPrefix(reference, "#") {
+		return "", false
+	}
+
+	parsed, err := url.Parse(reference)
+	if err != nil || parsed.Scheme != "" || parsed.Host != "" || parsed.Path == "" {
+		return "", false
+	}
+
+	return filepath.Join(filepath.Dir(document.Path), filepath.FromSlash(parsed.Path)), true
+}
+
+func plainText(node ast.Node, source []byte) string {
+	var builder strings.Builder
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		switch child := child.(type) {
+		case *ast.Text:
+			builder.Write(child.Value(source))
+		case *ast.String:
+			builder.Write(child.Value)
+		default:
+			builder.WriteString(plainText(child, source))
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,62 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalImagesAndReplaceImages(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "img"), 0o755); err != nil {
+		t.Fatalf("failed to create image dir: %v", err)
+	}
+	for _, name := range []string{"img/chart.png", "cover.jpg"} {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(name), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	chart := filepath.Join(dir, "img", "chart.png")
+	cover := filepath.Join(dir, "cover.jpg")
+	documents := map[string]Document{
+		"vi": {
+			FrontMatter: FrontMatter{FeaturedImage: "./cover.jpg"},
+			BodyMD:      "![Biểu đồ](./img/chart.png)\n\n![remote](https://example.com/a.png) ![site](/storage/b.png)",
+			Path:        filepath.Join(dir, "post.vi.md"),
+		},
+		"en": {
+			BodyMD: "![Chart *2024*](img/chart.png)",
+			Path:   filepath.Join(dir, "post.en.md"),
+		},
+	}
+
+	images, err := LocalImages(documents)
+	if err != nil {
+		t.Fatalf("failed to find images: %v", err)
+	}
+	if len(images) != 2 || images[0].Path != cover || images[1].Path != chart {
+		t.Fatalf("unexpected images: %+v", images)
+	}
+	if images[1].AltText["vi"] != "Biểu đồ" || images[1].AltText["en"] != "Chart 2024" {
+		t.Fatalf("unexpected alt text: %v", images[1].AltText)
+	}
+
+	replaced, err := ReplaceImages(documents, map[string]string{chart: "https://cdn.test/chart.png", cover: "https://cdn.test/cover.jpg"})
+	if err != nil {
+		t.Fatalf("failed to replace images: %v", err)
+	}
+	if replaced["vi"].FrontMatter.FeaturedImage != "https://cdn.test/cover.jpg" {
+		t.Fatalf("expected featured image to be replaced, got %q", replaced["vi"].FrontMatter.FeaturedImage)
+	}
+	body := replaced["vi"].BodyHTML
+	if !strings.Contains(body, `src="https://cdn.test/chart.png"`) || !strings.Contains(body, `src="https://example.com/a.png"`) || !strings.Contains(body, `src="/storage/b.png"`) {
+		t.Fatalf("unexpected body: %s", body)
+	}
+
+	documents["en"] = Document{BodyMD: "![missing](./missing.png)", Path: filepath.Join(dir, "post.en.md")}
+	if _, err := LocalImages(documents); err == nil {
+		t.Fatal("expected an error for a missing image")
+	}
+}
@@ -0,0 +1,214 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Localizable is a front matter field that is either one value for every
+// locale or a map of values by locale:
+//
+//	title: Same everywhere
+//	title: {vi: Xin chào, en: Hello}
+type Localizable map[string]string
+
+func (l *Localizable) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		*l = Localizable{"": node.Value}
+
+		return nil
+	case yaml.MappingNode:
+		values := map[string]string{}
+		if err := node.Decode(&values); err != nil {
+			return err
+		}
+
+		for locale := range values {
+			if !ValidLocale(locale) {
+				return fmt.Errorf("line %d: invalid locale %q", node.Line, locale)
+			}
+		}
+		*l = values
+
+		return nil
+	default:
+		return fmt.Errorf("line %d: expected a string or a map of locale to string", node.Line)
+	}
+}
+
+// Get returns the value for locale, or the shared value.
+func (l Localizable) Get(locale string) string {
+	if value, ok := l[locale]; ok {
+		return value
+	}
+
+	return l[""]
+}
+
+// MultilingualFrontMatter is the front matter of a single-file post. Fields
+// other than the Localizable ones are shared by every locale.
+type MultilingualFrontMatter struct {
+	Slug            string         `yaml:"slug"`
+	Title           Localizable    `yaml:"title"`
+	Excerpt         Localizable    `yaml:"excerpt"`
+	CategorySlug    string         `yaml:"category_slug"`
+	Status          string         `yaml:"status"`
+	Tags            []string       `yaml:"tags"`
+	MetaTitle       Localizable    `yaml:"meta_title"`
+	MetaDescription Localizable    `yaml:"meta_description"`
+	FeaturedImage   string         `yaml:"featured_image"`
+	OGImage         string         `yaml:"og_image"`
+	PublishedAt     string         `yaml:"published_at"`
+	ScheduledAt     string         `yaml:"scheduled_at"`
+	IsFeatured      *bool          `yaml:"is_featured"`
+	Markdown        *RenderOptions `yaml:"markdown"`
+}
+
+// For returns the front matter of one locale.
+func (m MultilingualFrontMatter) For(locale string) FrontMatter {
+	return FrontMatter{
+		Slug:            m.Slug,
+		Title:           m.Title.Get(locale),
+		Excerpt:         m.Excerpt.Get(locale),
+		CategorySlug:    m.CategorySlug,
+		Status:          m.Status,
+		Tags:            m.Tags,
+		MetaTitle:       m.MetaTitle.Get(locale),
+		MetaDescription: m.MetaDescription.Get(locale),
+		FeaturedImage:   m.FeaturedImage,
+		OGImage:         m.OGImage,
+		PublishedAt:     m.PublishedAt,
+		ScheduledAt:     m.ScheduledAt,
+		IsFeatured:      m.IsFeatured,
+		Markdown:        m.Markdown,
+	}
+}
+
+var langMarkerPattern = regexp.MustCompile(`^\s*<!--\s*lang:\s*(\S+?)\s*-->\s*$`)
+
+// ParseMultilingualFile reads a post whose locales share one file: front
+// matter with Localizable fields, then one body section per locale, each
+// starting with a <!-- lang:xx --> line. It returns a Document per locale.
+func ParseMultilingualFile(filePath string) (map[string]Document, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	fmRaw, body, err := splitFrontMatter(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var frontMatter MultilingualFrontMatter
+	if err := yaml.Unmarshal([]byte(fmRaw), &frontMatter); err != nil {
+		return nil, fmt.Errorf("invalid front matter: %w", err)
+	}
+
+	sections, err := splitLocaleSections(body)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range []Localizable{frontMatter.Title, frontMatter.Excerpt, frontMatter.MetaTitle, frontMatter.MetaDescription} {
+		for locale := range field {
+			if _, ok := sections[locale]; locale != "" && !ok {
+				return nil, fmt.Errorf("front matter has a %s value but the body has no <!-- lang:%s --> section", locale, locale)
+			}
+		}
+	}
+
+	options, err := renderOptionsFor(filePath, frontMatter.Markdown)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := make(map[string]Document, len(sections))
+	for locale, section := range sections {
+		document, err := newDocument(frontMatter.For(locale), section, options)
+		if err != nil {
+			return nil, fmt.Errorf("locale %s: %w", locale, err)
+		}
+		document.Path = filePath
+
+		documents[locale] = document
+	}
+
+	return documents, nil
+}
+
+// splitLocaleSections splits body at its <!-- lang:xx --> lines. Markers
+// inside fenced code blocks are part of the code.
+func splitLocaleSections(body string) (map[string]string, error) {
+	sections := map[string]string{}
+	lines := map[string][]string{}
+	locale := ""
+	fence := ""
+
+	for line := range strings.SplitSeq(body, "\n") {
+		switch marker, info := codeFence(line); {
+		case marker != "" && fence == "":
+			fence = marker
+		case marker != "" && info == "" && marker[0] == fence[0] && len(marker) >= len(fence):
+			fence = ""
+		case fence == "":
+			if match := langMarkerPattern.FindStringSubmatch(line); match != nil {
+				locale = match[1]
+				if !ValidLocale(locale) {
+					return nil, fmt.Errorf("invalid locale %q in %s", locale, strings.TrimSpace(line))
+				}
+				if _, ok := lines[locale]; ok {
+					return nil, fmt.Errorf("locale %s has more than one body section", locale)
+				}
+
+				lines[locale] = []string{}
+
+				continue
+			}
+		}
+
+		if locale == "" {
+			if strings.TrimSpace(line) != "" {
+				return nil, errors.New("body text must follow a <!-- lang:xx --> marker")
+			}
+
+			continue
+		}
+
+		lines[locale] = append(lines[locale], line)
+	}
+
+	if len(lines) == 0 {
+		return nil, errors.New("body has no <!-- lang:xx --> sections")
+	}
+
+	for locale, sectionLines := range lines {
+		sections[locale] = strings.TrimSpace(strings.Join(sectionLines, "\n"))
+	}
+
+	return sections, nil
+}
+
+// codeFence returns the run of backticks or tildes that opens or closes a
+// fenced code block on line and the text after it, or "" when line is not a
+// fence.
+func codeFence(line string) (string, string) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || trimmed == "" || (trimmed[0] != '`' && trimmed[0] != '~') {
+		return "", ""
+	}
+
+	rest := strings.TrimLeft(trimmed, trimmed[:1])
+	marker := trimmed[:len(trimmed)-len(rest)]
+	if len(marker) < 3 {
+		return "", ""
+	}
+
+	return marker, strings.TrimSpace(rest)
+}
@@ -0,0 +1,83 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseMultilingualFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "post.md")
+	content := `---
+slug: post-demo
+title:
+  vi: Bai viet demo
+  en: Demo post
+excerpt: Shared excerpt
+category_slug: tin-tuc
+tags: [ai]
+---
+<!-- lang:vi -->
+# Tieu de
+
+<!-- lang:en -->
+# Title
+`
+	if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write markdown file: %v", err)
+	}
+
+	documents, err := ParseMultilingualFile(filePath)
+	if err != nil {
+		t.Fatalf("parse markdown failed: %v", err)
+	}
+
+	if len(documents) != 2 {
+		t.Fatalf("expected two locales, got %v", documents)
+	}
+	if documents["en"].FrontMatter.Title != "Demo post" || documents["vi"].FrontMatter.Excerpt != "Shared excerpt" {
+		t.Fatalf("unexpected front matter: %#v", documents)
+	}
+	if documents["vi"].BodyMD != "# Tieu de" || !strings.Contains(documents["en"].BodyHTML, `<h1 id="title">Title</h1>`) {
+		t.Fatalf("unexpected bodies: %#v", documents)
+	}
+	if documents["vi"].FrontMatter.Status != "draft" || documents["en"].FrontMatter.Tags[0] != "ai" {
+		t.Fatalf("expected shared fields in every locale, got %#v", documents)
+	}
+}
+
+func TestSplitLocaleSectionsIgnoresMarkersInCode(t *testing.T) {
+	body := "<!-- lang:vi -->\nVí dụ:\n\n````markdown\n<!-- lang:en -->\n```\nEnglish\n````\n\n<!-- lang:en -->\nExample\n"
+
+	sections, err := splitLocaleSections(body)
+	if err != nil {
+		t.Fatalf("split failed: %v", err)
+	}
+
+	expected := "Ví dụ:\n\n````markdown\n<!-- lang:en -->\n```\nEnglish\n````"
+	if len(sections) != 2 || sections["vi"] != expected || sections["en"] != "Example" {
+		t.Fatalf("expected the fenced marker to stay in the vi code sample, got %#v", sections)
+	}
+}
+
+func TestParseMultilingualFileErrors(t *testing.T) {
+	tests := map[string]string{
+		"no sections":     "---\nslug: a\ntitle: A\ncategory_slug: c\n---\nBody",
+		"text before":     "---\nslug: a\ntitle: A\ncategory_slug: c\n---\nIntro\n<!-- lang:vi -->\nBody",
+		"duplicate":       "---\nslug: a\ntitle: A\ncategory_slug: c\n---\n<!-- lang:vi -->\nA\n<!-- lang:vi -->\nB",
+		"missing section": "---\nslug: a\ntitle: {vi: A, ja: B}\ncategory_slug: c\n---\n<!-- lang:vi -->\nA",
+		"missing title":   "---\nslug: a\ntitle: {vi: A}\ncategory_slug: c\n---\n<!-- lang:vi -->\nA\n<!-- lang:en -->\nB",
+	}
+
+	for name, content := range tests {
+		filePath := filepath.Join(t.TempDir(), "post.md")
+		if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write markdown file: %v", err)
+		}
+
+		if _, err := ParseMultilingualFile(filePath); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
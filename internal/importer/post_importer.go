@@ -5,32 +5,43 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
-	"github.com/yuin/goldmark"
 	"gopkg.in/yaml.v3"
 )
 
 type FrontMatter struct {
 	Slug            string   `yaml:"slug"`
 	Title           string   `yaml:"title"`
-	Excerpt         string   `yaml:"excerpt"`
+	Excerpt         string   `yaml:"excerpt,omitempty"`
 	CategorySlug    string   `yaml:"category_slug"`
-	Status          string   `yaml:"status"`
-	Tags            []string `yaml:"tags"`
-	MetaTitle       string   `yaml:"meta_title"`
-	MetaDescription string   `yaml:"meta_description"`
-	FeaturedImage   string   `yaml:"featured_image"`
-	OGImage         string   `yaml:"og_image"`
-	PublishedAt     string   `yaml:"published_at"`
-	ScheduledAt     string   `yaml:"scheduled_at"`
-	IsFeatured      *bool    `yaml:"is_featured"`
+	Status          string   `yaml:"status,omitempty"`
+	Tags            []string `yaml:"tags,omitempty"`
+	MetaTitle       string   `yaml:"meta_title,omitempty"`
+	MetaDescription string   `yaml:"meta_description,omitempty"`
+	FeaturedImage   string   `yaml:"featured_image,omitempty"`
+	OGImage         string   `yaml:"og_image,omitempty"`
+	PublishedAt     string   `yaml:"published_at,omitempty"`
+	ScheduledAt     string   `yaml:"scheduled_at,omitempty"`
+	IsFeatured      *bool    `yaml:"is_featured,omitempty"`
+	// Markdown overrides the render options of the project config.
+	Markdown *RenderOptions `yaml:"markdown,omitempty"`
 }
 
 type Document struct {
 	FrontMatter FrontMatter
 	BodyMD      string
 	BodyHTML    string
+	// Path is the file the document was read from. Relative image paths are
+	// resolved against its directory.
+	Path string
+	// Options are the render options BodyHTML was made with.
+	Options RenderOptions
+	// Shortcodes are expanded in BodyHTML when set; see ExpandShortcodes.
+	Shortcodes *Shortcodes
 }
 
 func ParseMarkdownFile(filePath string) (Document, error) {
@@ -49,6 +60,19 @@ func ParseMarkdownFile(filePath string) (Document, error) {
 		return Document{}, fmt.Errorf("invalid front matter: %w", err)
 	}
 
+	options, err := renderOptionsFor(filePath, frontMatter.Markdown)
+	if err != nil {
+		return Document{}, err
+	}
+
+	document, err := newDocument(frontMatter, body, options)
+	document.Path = filePath
+
+	return document, err
+}
+
+// newDocument checks the required front matter fields and renders body.
+func newDocument(frontMatter FrontMatter, body string, options RenderOptions) (Document, error) {
 	if strings.TrimSpace(frontMatter.Slug) == "" {
 		return Document{}, errors.New("front matter field 'slug' is required")
 	}
@@ -65,7 +89,7 @@ func ParseMarkdownFile(filePath string) (Document, error) {
 		frontMatter.Status = "draft"
 	}
 
-	bodyHTML, err := markdownToHTML(body)
+	bodyHTML, err := renderMarkdown(body, options, nil, "", nil)
 	if err != nil {
 		return Document{}, err
 	}
@@ -74,76 +98,183 @@ func ParseMarkdownFile(filePath string) (Document, error) {
 		FrontMatter: frontMatter,
 		BodyMD:      body,
 		BodyHTML:    bodyHTML,
+		Options:     options,
 	}, nil
 }
 
+// LocaleOptions controls which locales a post import needs.
+type LocaleOptions struct {
+	// Required locales must each have a document.
+	Required []string
+	// Fallback is the locale whose front matter supplies the fields shared by
+	// every locale, such as slug, category, tags and dates, before the other
+	// locales are consulted. When empty it is the first required locale, or
+	// else the first locale in sorted order.
+	Fallback string
+}
+
+// DefaultLocales are the Vietnamese and English editions every post has.
+var DefaultLocales = LocaleOptions{Required: []string{"vi", "en"}, Fallback: "vi"}
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// ValidLocale reports whether locale looks like a language tag such as "ja"
+// or "pt-BR".
+func ValidLocale(locale string) bool {
+	return localePattern.MatchString(locale)
+}
+
+// LocaleFromPath returns the locale of a file named like post.ja.md.
+func LocaleFromPath(filePath string) (string, bool) {
+	name, ok := strings.CutSuffix(filepath.Base(filePath), ".md")
+	if !ok {
+		return "", false
+	}
+
+	dot := strings.LastIndex(name, ".")
+	if dot < 0 || !ValidLocale(name[dot+1:]) {
+		return "", false
+	}
+
+	return name[dot+1:], true
+}
+
 func BuildBilingualPostPayload(viDoc Document, enDoc Document, categoryID int, tagIDs []int) (map[string]any, error) {
-	if viDoc.FrontMatter.Slug != enDoc.FrontMatter.Slug {
-		return nil, errors.New("slug mismatch between Vietnamese and English markdown files")
+	return BuildPostPayload(map[string]Document{"vi": viDoc, "en": enDoc}, DefaultLocales, categoryID, tagIDs)
+}
+
+// BuildPostPayload builds a post with one entry per locale in each
+// localized field.
+func BuildPostPayload(documents map[string]Document, options LocaleOptions, categoryID int, tagIDs []int) (map[string]any, error) {
+	for _, locale := range options.Required {
+		if _, ok := documents[locale]; !ok {
+			return nil, fmt.Errorf("missing markdown file for locale %q", locale)
+		}
 	}
 
-	if viDoc.FrontMatter.CategorySlug != enDoc.FrontMatter.CategorySlug {
-		return nil, errors.New("category_slug mismatch between Vietnamese and English markdown files")
+	locales := OrderLocales(documents, options)
+	if len(locales) == 0 {
+		return nil, errors.New("at least one markdown file is required")
+	}
+	if options.Fallback != "" && locales[0] != options.Fallback {
+		return nil, fmt.Errorf("missing markdown file for fallback locale %q", options.Fallback)
 	}
 
-	status := viDoc.FrontMatter.Status
-	if status == "" {
-		status = enDoc.FrontMatter.Status
+	primary := documents[locales[0]].FrontMatter
+	for _, locale := range locales[1:] {
+		frontMatter := documents[locale].FrontMatter
+		if frontMatter.Slug != primary.Slug {
+			return nil, fmt.Errorf("slug mismatch between %s and %s markdown files", locales[0], locale)
+		}
+
+		if frontMatter.CategorySlug != primary.CategorySlug {
+			return nil, fmt.Errorf("category_slug mismatch between %s and %s markdown files", locales[0], locale)
+		}
 	}
+
+	localized := func(value func(Document) string) map[string]string {
+		values := make(map[string]string, len(locales))
+		for _, locale := range locales {
+			values[locale] = value(documents[locale])
+		}
+
+		return values
+	}
+	shared := func(value func(FrontMatter) string) string {
+		for _, locale := range locales {
+			if candidate := value(documents[locale].FrontMatter); strings.TrimSpace(candidate) != "" {
+				return candidate
+			}
+		}
+
+		return ""
+	}
+
+	status := shared(func(frontMatter FrontMatter) string { return frontMatter.Status })
 	if status == "" {
 		status = "draft"
 	}
 
 	payload := map[string]any{
-		"slug": viDoc.FrontMatter.Slug,
-		"title": map[string]string{
-			"vi": viDoc.FrontMatter.Title,
-			"en": enDoc.FrontMatter.Title,
-		},
-		"excerpt": map[string]string{
-			"vi": viDoc.FrontMatter.Excerpt,
-			"en": enDoc.FrontMatter.Excerpt,
-		},
-		"body": map[string]string{
-			"vi": viDoc.BodyHTML,
-			"en": enDoc.BodyHTML,
-		},
-		"category_id": categoryID,
-		"status":      status,
-		"tags":        tagIDs,
-		"meta_title": map[string]string{
-			"vi": viDoc.FrontMatter.MetaTitle,
-			"en": enDoc.FrontMatter.MetaTitle,
-		},
-		"meta_description": map[string]string{
-			"vi": viDoc.FrontMatter.MetaDescription,
-			"en": enDoc.FrontMatter.MetaDescription,
-		},
-		"featured_image": firstNonEmpty(viDoc.FrontMatter.FeaturedImage, enDoc.FrontMatter.FeaturedImage),
-		"og_image":       firstNonEmpty(viDoc.FrontMatter.OGImage, enDoc.FrontMatter.OGImage),
-	}
-
-	if viDoc.FrontMatter.PublishedAt != "" {
-		payload["published_at"] = viDoc.FrontMatter.PublishedAt
-	} else if enDoc.FrontMatter.PublishedAt != "" {
-		payload["published_at"] = enDoc.FrontMatter.PublishedAt
-	}
-
-	if viDoc.FrontMatter.ScheduledAt != "" {
-		payload["scheduled_at"] = viDoc.FrontMatter.ScheduledAt
-	} else if enDoc.FrontMatter.ScheduledAt != "" {
-		payload["scheduled_at"] = enDoc.FrontMatter.ScheduledAt
-	}
-
-	if viDoc.FrontMatter.IsFeatured != nil {
-		payload["is_featured"] = *viDoc.FrontMatter.IsFeatured
-	} else if enDoc.FrontMatter.IsFeatured != nil {
-		payload["is_featured"] = *enDoc.FrontMatter.IsFeatured
+		"slug":             primary.Slug,
+		"title":            localized(func(document Document) string { return document.FrontMatter.Title }),
+		"excerpt":          localized(func(document Document) string { return document.FrontMatter.Excerpt }),
+		"body":             localized(func(document Document) string { return document.BodyHTML }),
+		"category_id":      categoryID,
+		"status":           status,
+		"tags":             tagIDs,
+		"meta_title":       localized(func(document Document) string { return document.FrontMatter.MetaTitle }),
+		"meta_description": localized(func(document Document) string { return document.FrontMatter.MetaDescription }),
+		"featured_image":   shared(func(frontMatter FrontMatter) string { return frontMatter.FeaturedImage }),
+		"og_image":         shared(func(frontMatter FrontMatter) string { return frontMatter.OGImage }),
+	}
+
+	if publishedAt := shared(func(frontMatter FrontMatter) string { return frontMatter.PublishedAt }); publishedAt != "" {
+		payload["published_at"] = publishedAt
+	}
+
+	if scheduledAt := shared(func(frontMatter FrontMatter) string { return frontMatter.ScheduledAt }); scheduledAt != "" {
+		payload["scheduled_at"] = scheduledAt
+	}
+
+	for _, locale := range locales {
+		if isFeatured := documents[locale].FrontMatter.IsFeatured; isFeatured != nil {
+			payload["is_featured"] = *isFeatured
+
+			break
+		}
 	}
 
 	return payload, nil
 }
 
+// OrderLocales lists the locales of documents with the fallback first, then
+// the other required locales in order, then the rest sorted.
+func OrderLocales(documents map[string]Document, options LocaleOptions) []string {
+	locales := make([]string, 0, len(documents))
+	seen := map[string]bool{}
+	add := func(locale string) {
+		if _, ok := documents[locale]; ok && !seen[locale] {
+			seen[locale] = true
+			locales = append(locales, locale)
+		}
+	}
+
+	add(options.Fallback)
+	for _, locale := range options.Required {
+		add(locale)
+	}
+
+	rest := make([]string, 0, len(documents))
+	for locale := range documents {
+		rest = append(rest, locale)
+	}
+	sort.Strings(rest)
+	for _, locale := range rest {
+		add(locale)
+	}
+
+	return locales
+}
+
+// FormatMarkdown renders a document the way ParseMarkdownFile reads it:
+// YAML front matter followed by the Markdown body.
+func FormatMarkdown(frontMatter FrontMatter, bodyMD string) ([]byte, error) {
+	encoded, err := yaml.Marshal(frontMatter)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString("---\n")
+	buffer.Write(encoded)
+	buffer.WriteString("---\n\n")
+	buffer.WriteString(strings.TrimSpace(bodyMD))
+	buffer.WriteString("\n")
+
+	return buffer.Bytes(), nil
+}
+
 func splitFrontMatter(content string) (string, string, error) {
 	trimmed := strings.TrimSpace(content)
 	if !strings.HasPrefix(trimmed, "---") {
@@ -176,22 +307,3 @@ func splitFrontMatter(content string) (string, string, error) {
 
 	return frontMatter, strings.TrimSpace(body), nil
 }
-
-func markdownToHTML(markdownText string) (string, error) {
-	var buffer bytes.Buffer
-	if err := goldmark.Convert([]byte(markdownText), &buffer); err != nil {
-		return "", err
-	}
-
-	return strings.TrimSpace(buffer.String()), nil
-}
-
-func firstNonEmpty(values ...string) string {
-	for _, value := range values {
-		if strings.TrimSpace(value) != "" {
-			return value
-		}
-	}
-
-	return ""
-}
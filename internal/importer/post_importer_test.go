@@ -49,3 +49,51 @@ func TestBuildBilingualPostPayloadSlugMismatch(t *testing.T) {
 		t.Fatal("expected slug mismatch error")
 	}
 }
+
+func TestBuildPostPayloadWithExtraLocales(t *testing.T) {
+	featured := true
+	documents := map[string]Document{
+		"vi": {FrontMatter: FrontMatter{Slug: "post", CategorySlug: "news", Title: "Xin chao"}, BodyHTML: "<p>vi</p>"},
+		"en": {FrontMatter: FrontMatter{Slug: "post", CategorySlug: "news", Title: "Hello", Status: "published"}},
+		"ja": {FrontMatter: FrontMatter{Slug: "post", CategorySlug: "news", Title: "Konnichiwa", IsFeatured: &featured}},
+	}
+
+	payload, err := BuildPostPayload(documents, DefaultLocales, 10, nil)
+	if err != nil {
+		t.Fatalf("build payload failed: %v", err)
+	}
+
+	title := payload["title"].(map[string]string)
+	if len(title) != 3 || title["ja"] != "Konnichiwa" || title["vi"] != "Xin chao" {
+		t.Fatalf("unexpected title: %v", title)
+	}
+	if payload["status"] != "published" || payload["is_featured"] != true {
+		t.Fatalf("expected shared fields from the other locales, got %v", payload)
+	}
+
+	delete(documents, "en")
+	if _, err := BuildPostPayload(documents, DefaultLocales, 10, nil); err == nil {
+		t.Fatal("expected missing required locale error")
+	}
+
+	if _, err := BuildPostPayload(documents, LocaleOptions{Required: []string{"ja"}, Fallback: "ko"}, 10, nil); err == nil {
+		t.Fatal("expected missing fallback locale error")
+	}
+}
+
+func TestLocaleFromPath(t *testing.T) {
+	tests := map[string]string{
+		"post.ja.md":        "ja",
+		"dir/post.pt-BR.md": "pt-BR",
+		"post.md":           "",
+		"post.ja.txt":       "",
+		"post.Japanese.md":  "",
+	}
+
+	for filePath, expected := range tests {
+		locale, ok := LocaleFromPath(filePath)
+		if locale != expected || ok != (expected != "") {
+			t.Fatalf("LocaleFromPath(%q) = %q, %v; expected %q", filePath, locale, ok, expected)
+		}
+	}
+}
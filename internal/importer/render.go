@@ -0,0 +1,270 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"gopkg.in/yaml.v3"
+)
+
+// Unset fields keep the value from the level below, so the project config
+// and the front matter can each change only some of them.
+type RenderOptions struct {
+	GFM             *bool `yaml:"gfm,omitempty"`
+	Footnotes       *bool `yaml:"footnotes,omitempty"`
+	DefinitionLists *bool `yaml:"definition_lists,omitempty"`
+	Typographer     *bool `yaml:"typographer,omitempty"`
+	HeadingIDs      *bool `yaml:"heading_ids,omitempty"`
+	Highlight       *bool `yaml:"highlight,omitempty"`
+}
+
+// DefaultRenderOptions enable everything but the typographer, which would
+// change the text itself.
+var DefaultRenderOptions = RenderOptions{
+	GFM:             boolPtr(true),
+	Footnotes:       boolPtr(true),
+	DefinitionLists: boolPtr(true),
+	Typographer:     boolPtr(false),
+	HeadingIDs:      boolPtr(true),
+	Highlight:       boolPtr(true),
+}
+
+func (o RenderOptions) Merge(override RenderOptions) RenderOptions {
+	pick := func(base *bool, value *bool) *bool {
+		if value != nil {
+			return value
+		}
+
+		return base
+	}
+
+	return RenderOptions{
+		GFM:             pick(o.GFM, override.GFM),
+		Footnotes:       pick(o.Footnotes, override.Footnotes),
+		DefinitionLists: pick(o.DefinitionLists, override.DefinitionLists),
+		Typographer:     pick(o.Typographer, override.Typographer),
+		HeadingIDs:      pick(o.HeadingIDs, override.HeadingIDs),
+		Highlight:       pick(o.Highlight, override.Highlight),
+	}
+}
+
+// The search for ProjectFile stops at the git repository root, or at the
+// Markdown file's own directory outside a repository, so a stray
+// ~/.geda.yaml never changes an import.
+const ProjectFile = ".geda.yaml"
+
+type ProjectConfig struct {
+	Markdown RenderOptions `yaml:"markdown"`
+	// Shortcodes is relative to Dir.
+	Shortcodes string `yaml:"shortcodes"`
+	Dir        string `yaml:"-"`
+}
+
+func LoadProjectConfig(dir string) (ProjectConfig, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ProjectConfig{}, err
+	}
+
+	for _, candidate := range projectDirs(dir) {
+		filePath := filepath.Join(candidate, ProjectFile)
+		content, err := os.ReadFile(filePath)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return ProjectConfig{}, err
+		}
+
+		var config ProjectConfig
+		if err := yaml.Unmarshal(content, &config); err != nil {
+			return ProjectConfig{}, fmt.Errorf("invalid %s: %w", filePath, err)
+		}
+		config.Dir = candidate
+
+		return config, nil
+	}
+
+	return ProjectConfig{}, nil
+}
+
+func projectDirs(dir string) []string {
+	dirs := []string{}
+	for current := dir; ; {
+		dirs = append(dirs, current)
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return dirs
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return []string{dir}
+		}
+		current = parent
+	}
+}
+
+func renderOptionsFor(filePath string, frontMatter *RenderOptions) (RenderOptions, error) {
+	project, err := LoadProjectConfig(filepath.Dir(filePath))
+	if err != nil {
+		return RenderOptions{}, err
+	}
+
+	options := DefaultRenderOptions.Merge(project.Markdown)
+	if frontMatter != nil {
+		options = options.Merge(*frontMatter)
+	}
+
+	return options, nil
+}
+
+type rendererKey struct {
+	gfm, footnotes, definitionLists, typographer, headingIDs, highlight bool
+}
+
+func (o RenderOptions) key() rendererKey {
+	value := func(field *bool) bool {
+		return field != nil && *field
+	}
+
+	return rendererKey{
+		gfm:             value(o.GFM),
+		footnotes:       value(o.Footnotes),
+		definitionLists: value(o.DefinitionLists),
+		typographer:     value(o.Typographer),
+		headingIDs:      value(o.HeadingIDs),
+		highlight:       value(o.Highlight),
+	}
+}
+
+var renderers sync.Map
+
+func renderer(options RenderOptions) goldmark.Markdown {
+	key := options.key()
+	if markdown, ok := renderers.Load(key); ok {
+		return markdown.(goldmark.Markdown)
+	}
+
+	extensions := []goldmark.Extender{}
+	if key.gfm {
+		extensions = append(extensions, extension.GFM)
+	}
+	if key.footnotes {
+		extensions = append(extensions, extension.Footnote)
+	}
+	if key.definitionLists {
+		extensions = append(extensions, extension.DefinitionList)
+	}
+	if key.typographer {
+		extensions = append(extensions, extension.Typographer)
+	}
+	if key.highlight {
+		extensions = append(extensions, highlighting.NewHighlighting(
+			highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+		))
+	}
+
+	parserOptions := []parser.Option{}
+	if key.headingIDs {
+		parserOptions = append(parserOptions, parser.WithAutoHeadingID(), parser.WithHeadingAttribute())
+	}
+
+	markdown, _ := renderers.LoadOrStore(key, goldmark.New(
+		goldmark.WithExtensions(extensions...),
+		goldmark.WithParserOptions(parserOptions...),
+	))
+
+	return markdown.(goldmark.Markdown)
+}
+
+func renderMarkdown(markdownText string, options RenderOptions, shortcodes *Shortcodes, locale string, visitImage func(image *ast.Image, source []byte) error) (string, error) {
+	var placeholders map[string]string
+	if shortcodes != nil {
+		var err error
+		markdownText, placeholders, err = shortcodes.expand(markdownText, locale, func(inner string) (string, error) {
+			return renderMarkdown(inner, options, shortcodes, locale, visitImage)
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	markdown := renderer(options)
+	source := []byte(markdownText)
+	document := markdown.Parser().Parse(text.NewReader(source), parser.WithContext(parser.NewContext(parser.WithIDs(&headingIDs{used: map[string]bool{}}))))
+
+	if visitImage != nil {
+		err := ast.Walk(document, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+			if image, ok := node.(*ast.Image); ok && entering {
+				return ast.WalkContinue, visitImage(image, source)
+			}
+
+			return ast.WalkContinue, nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := markdown.Renderer().Render(&buffer, source, document); err != nil {
+		return "", err
+	}
+
+	return restoreShortcodes(strings.TrimSpace(buffer.String()), placeholders), nil
+}
+
+// headingIDs is goldmark's id generator, except that it keeps letters
+// outside ASCII so "Mở đầu" becomes "mở-đầu" rather than "m-u".
+type headingIDs struct {
+	used map[string]bool
+}
+
+func (ids *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	var builder strings.Builder
+	for _, char := range strings.TrimSpace(string(value)) {
+		switch {
+		case unicode.IsLetter(char) || unicode.IsDigit(char):
+			builder.WriteRune(unicode.ToLower(char))
+		case unicode.IsSpace(char) || char == '-' || char == '_':
+			builder.WriteRune('-')
+		}
+	}
+
+	id := builder.String()
+	if id == "" {
+		id = "id"
+		if kind == ast.KindHeading {
+			id = "heading"
+		}
+	}
+
+	unique := id
+	for i := 1; ids.used[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", id, i)
+	}
+	ids.used[unique] = true
+
+	return []byte(unique)
+}
+
+func (ids *headingIDs) Put(value []byte) {
+	ids.used[string(value)] = true
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
@@ -0,0 +1,132 @@
+package importer
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestRenderGolden renders each testdata/render/*.md with the default
+// options changed only by its front matter, ignoring any project config,
+// and compares the body with the .golden.html file next to it. Run with
+// -update to accept new output.
+func TestRenderGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "render", "*.md"))
+	if err != nil {
+		t.Fatalf("failed to list inputs: %v", err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("expected golden inputs in testdata/render")
+	}
+
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".md")
+		t.Run(name, func(t *testing.T) {
+			content, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatalf("failed to read input: %v", err)
+			}
+			rawFrontMatter, body, err := splitFrontMatter(string(content))
+			if err != nil {
+				t.Fatalf("failed to split front matter: %v", err)
+			}
+			var frontMatter FrontMatter
+			if err := yaml.Unmarshal([]byte(rawFrontMatter), &frontMatter); err != nil {
+				t.Fatalf("invalid front matter: %v", err)
+			}
+
+			options := DefaultRenderOptions
+			if frontMatter.Markdown != nil {
+				options = options.Merge(*frontMatter.Markdown)
+			}
+			document, err := newDocument(frontMatter, body, options)
+			if err != nil {
+				t.Fatalf("parse markdown failed: %v", err)
+			}
+
+			golden := strings.TrimSuffix(input, ".md") + ".golden.html"
+			if *update {
+				if err := os.WriteFile(golden, []byte(document.BodyHTML+"\n"), 0o644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+			}
+
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			if document.BodyHTML+"\n" != string(expected) {
+				t.Fatalf("output differs from %s:\n%s", golden, document.BodyHTML)
+			}
+		})
+	}
+}
+
+func TestRenderOptionsFromProjectConfig(t *testing.T) {
+	outside := t.TempDir()
+	root := filepath.Join(outside, "site")
+	postDir := filepath.Join(root, "content", "posts")
+	for _, dir := range []string{postDir, filepath.Join(root, ".git")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+	}
+	for dir, config := range map[string]string{
+		root:    "markdown:\n  heading_ids: false\n  typographer: true\n",
+		outside: "markdown:\n  gfm: false\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, ProjectFile), []byte(config), 0o600); err != nil {
+			t.Fatalf("failed to write project config: %v", err)
+		}
+	}
+
+	write := func(frontMatter string) Document {
+		filePath := filepath.Join(postDir, "post.vi.md")
+		content := "---\nslug: a\ntitle: A\ncategory_slug: c\n" + frontMatter + "---\n# Title\n\n\"Hi\"\n"
+		if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write markdown: %v", err)
+		}
+
+		document, err := ParseMarkdownFile(filePath)
+		if err != nil {
+			t.Fatalf("parse markdown failed: %v", err)
+		}
+
+		return document
+	}
+
+	document := write("")
+	if document.BodyHTML != "<h1>Title</h1>\n<p>&ldquo;Hi&rdquo;</p>" {
+		t.Fatalf("expected the project config to apply, got %q", document.BodyHTML)
+	}
+
+	document = write("markdown:\n  heading_ids: true\n")
+	if !strings.HasPrefix(document.BodyHTML, `<h1 id="title">`) || !strings.Contains(document.BodyHTML, "&ldquo;") {
+		t.Fatalf("expected front matter to override only heading_ids, got %q", document.BodyHTML)
+	}
+
+	other := filepath.Join(outside, "other")
+	if err := os.MkdirAll(filepath.Join(other, ".git"), 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if config, err := LoadProjectConfig(other); err != nil || config.Dir != "" {
+		t.Fatalf("expected no project config above the repository root, got %+v (%v)", config, err)
+	}
+
+	if err := os.Remove(filepath.Join(root, ".git")); err != nil {
+		t.Fatalf("failed to remove .git: %v", err)
+	}
+	config, err := LoadProjectConfig(postDir)
+	if err != nil {
+		t.Fatalf("failed to load project config: %v", err)
+	}
+	if config.Dir != "" {
+		t.Fatalf("expected no project config above a directory outside a repository, got %+v", config)
+	}
+}
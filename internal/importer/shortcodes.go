@@ -0,0 +1,439 @@
+package importer
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Writing {{</* youtube id */>}} keeps the tag as text.
+type Shortcodes struct {
+	templates map[string]*template.Template
+	data      map[string]ShortcodeData
+}
+
+type ShortcodeCall struct {
+	Name   string
+	Locale string
+	Params []string
+	Named  map[string]string
+	Inner  template.HTML
+	Data   any
+}
+
+func (c ShortcodeCall) Get(key any) string {
+	switch key := key.(type) {
+	case string:
+		return c.Named[key]
+	case int:
+		if key >= 0 && key < len(c.Params) {
+			return c.Params[key]
+		}
+	}
+
+	return ""
+}
+
+// Maps of locale to text fall back to Vietnamese, like geda.Localized.Get.
+func (c ShortcodeCall) Localized(value any) string {
+	switch value := value.(type) {
+	case map[string]any:
+		text, _ := value[c.Locale].(string)
+		if text == "" {
+			text, _ = value[DefaultLocales.Fallback].(string)
+		}
+
+		return text
+	case map[string]string:
+		if text := value[c.Locale]; text != "" {
+			return text
+		}
+
+		return value[DefaultLocales.Fallback]
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+type ShortcodeData func(call ShortcodeCall) (any, error)
+
+//go:embed shortcodes/*.html
+var builtinShortcodes embed.FS
+
+func NewShortcodes() *Shortcodes {
+	shortcodes := &Shortcodes{templates: map[string]*template.Template{}, data: map[string]ShortcodeData{}}
+
+	entries, _ := builtinShortcodes.ReadDir("shortcodes")
+	for _, entry := range entries {
+		content, _ := builtinShortcodes.ReadFile(path.Join("shortcodes", entry.Name()))
+		if err := shortcodes.Register(strings.TrimSuffix(entry.Name(), ".html"), string(content)); err != nil {
+			panic(err)
+		}
+	}
+
+	return shortcodes
+}
+
+func (s *Shortcodes) Register(name string, source string) error {
+	if !validShortcodeName(name) {
+		return fmt.Errorf("invalid shortcode name %q", name)
+	}
+
+	parsed, err := template.New(name).Parse(source)
+	if err != nil {
+		return fmt.Errorf("shortcode %s: %w", name, err)
+	}
+
+	s.templates[name] = parsed
+
+	return nil
+}
+
+func (s *Shortcodes) LoadDir(dir string) error {
+	if _, err := os.Stat(dir); err != nil {
+		return err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return err
+	}
+
+	for _, filePath := range paths {
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+
+		if err := s.Register(strings.TrimSuffix(filepath.Base(filePath), ".html"), string(content)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Shortcodes in dirs replace those of the same name from the project config,
+// and later dirs replace earlier ones.
+func LoadShortcodes(markdownPath string, dirs ...string) (*Shortcodes, error) {
+	shortcodes := NewShortcodes()
+
+	project, err := LoadProjectConfig(filepath.Dir(markdownPath))
+	if err != nil {
+		return nil, err
+	}
+	if project.Shortcodes != "" {
+		dir := project.Shortcodes
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(project.Dir, dir)
+		}
+		dirs = append([]string{dir}, dirs...)
+	}
+
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+
+		if err := shortcodes.LoadDir(dir); err != nil {
+			return nil, fmt.Errorf("failed to load shortcodes: %w", err)
+		}
+	}
+
+	return shortcodes, nil
+}
+
+func (s *Shortcodes) SetData(name string, data ShortcodeData) {
+	s.data[name] = data
+}
+
+func (s *Shortcodes) execute(call ShortcodeCall) (string, error) {
+	tmpl, ok := s.templates[call.Name]
+	if !ok {
+		return "", fmt.Errorf("unknown shortcode %q", call.Name)
+	}
+
+	if data, ok := s.data[call.Name]; ok {
+		value, err := data(call)
+		if err != nil {
+			return "", fmt.Errorf("shortcode %s: %w", call.Name, err)
+		}
+		call.Data = value
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, call); err != nil {
+		return "", fmt.Errorf("shortcode %s: %w", call.Name, err)
+	}
+
+	return strings.TrimSpace(buffer.String()), nil
+}
+
+type shortcodeTag struct {
+	start, end  int
+	name        string
+	closing     bool
+	selfClosing bool
+	literal     string
+	params      []string
+	named       map[string]string
+}
+
+func (s *Shortcodes) expand(source string, locale string, renderInner func(string) (string, error)) (string, map[string]string, error) {
+	tags, err := findShortcodeTags(source)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var builder strings.Builder
+	placeholders := map[string]string{}
+	position := 0
+	for i := 0; i < len(tags); i++ {
+		tag := tags[i]
+		builder.WriteString(source[position:tag.start])
+		position = tag.end
+
+		if tag.literal != "" {
+			builder.WriteString(tag.literal)
+
+			continue
+		}
+		if tag.closing {
+			return "", nil, fmt.Errorf("closing shortcode {{< /%s >}} without an opening tag", tag.name)
+		}
+
+		call := ShortcodeCall{Name: tag.name, Locale: locale, Params: tag.params, Named: tag.named}
+		if closeIndex := matchingClose(tags, i); closeIndex >= 0 {
+			inner, err := renderInner(source[tag.end:tags[closeIndex].start])
+			if err != nil {
+				return "", nil, err
+			}
+
+			call.Inner = template.HTML(inner)
+			position = tags[closeIndex].end
+			i = closeIndex
+		}
+
+		expanded, err := s.execute(call)
+		if err != nil {
+			return "", nil, err
+		}
+
+		placeholder := "GEDASHORTCODE" + strconv.Itoa(len(placeholders)) + "Z"
+		placeholders[placeholder] = expanded
+		builder.WriteString(placeholder)
+	}
+	builder.WriteString(source[position:])
+
+	return builder.String(), placeholders, nil
+}
+
+// A placeholder that Markdown made a paragraph of its own loses the <p>.
+func restoreShortcodes(rendered string, placeholders map[string]string) string {
+	if len(placeholders) == 0 {
+		return rendered
+	}
+
+	pairs := make([]string, 0, len(placeholders)*4)
+	for placeholder, expanded := range placeholders {
+		pairs = append(pairs, "<p>"+placeholder+"</p>", expanded)
+	}
+	for placeholder, expanded := range placeholders {
+		pairs = append(pairs, placeholder, expanded)
+	}
+
+	return strings.NewReplacer(pairs...).Replace(rendered)
+}
+
+func matchingClose(tags []shortcodeTag, open int) int {
+	if tags[open].selfClosing {
+		return -1
+	}
+
+	depth := 1
+	for i := open + 1; i < len(tags); i++ {
+		tag := tags[i]
+		if tag.name != tags[open].name || tag.literal != "" || tag.selfClosing {
+			continue
+		}
+
+		if tag.closing {
+			depth--
+		} else {
+			depth++
+		}
+		if depth == 0 {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func findShortcodeTags(source string) ([]shortcodeTag, error) {
+	tags := []shortcodeTag{}
+	offset := 0
+	for {
+		start := strings.Index(source[offset:], "{{<")
+		if start < 0 {
+			return tags, nil
+		}
+		start += offset
+
+		end := strings.Index(source[start:], ">}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed shortcode at %q", excerpt(source[start:]))
+		}
+		end += start + len(">}}")
+
+		tag, err := parseShortcodeTag(source[start+len("{{<") : end-len(">}}")])
+		if err != nil {
+			return nil, fmt.Errorf("%w in %s", err, source[start:end])
+		}
+		tag.start, tag.end = start, end
+		if tag.literal != "" {
+			tag.literal = "{{<" + tag.literal + ">}}"
+		}
+
+		tags = append(tags, tag)
+		offset = end
+	}
+}
+
+func parseShortcodeTag(content string) (shortcodeTag, error) {
+	trimmed := strings.TrimSpace(content)
+	if inner, ok := strings.CutPrefix(trimmed, "/*"); ok {
+		if inner, ok := strings.CutSuffix(inner, "*/"); ok {
+			return shortcodeTag{literal: " " + strings.TrimSpace(inner) + " "}, nil
+		}
+	}
+
+	tag := shortcodeTag{named: map[string]string{}}
+	if rest, ok := strings.CutSuffix(trimmed, "/"); ok {
+		tag.selfClosing = true
+		trimmed = rest
+	}
+	if rest, ok := strings.CutPrefix(trimmed, "/"); ok {
+		tag.closing = true
+		trimmed = rest
+	}
+
+	fields, err := splitShortcodeArgs(trimmed)
+	if err != nil {
+		return shortcodeTag{}, err
+	}
+	if len(fields) == 0 || !validShortcodeName(fields[0]) {
+		return shortcodeTag{}, errors.New("invalid shortcode name")
+	}
+	tag.name = fields[0]
+
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if ok && validShortcodeName(key) {
+			tag.named[key] = unquote(value)
+
+			continue
+		}
+
+		tag.params = append(tag.params, unquote(field))
+	}
+	if tag.closing && (len(tag.params) > 0 || len(tag.named) > 0) {
+		return shortcodeTag{}, errors.New("closing shortcode takes no arguments")
+	}
+
+	return tag, nil
+}
+
+func splitShortcodeArgs(content string) ([]string, error) {
+	fields := []string{}
+	var current strings.Builder
+	inQuotes, escaped, started := false, false, false
+
+	for _, char := range content {
+		switch {
+		case escaped:
+			current.WriteRune(char)
+			escaped = false
+		case char == '\\' && inQuotes:
+			current.WriteRune(char)
+			escaped = true
+		case char == '"':
+			current.WriteRune(char)
+			inQuotes = !inQuotes
+			started = true
+		case unicode.IsSpace(char) && !inQuotes:
+			if started {
+				fields = append(fields, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(char)
+			started = true
+		}
+	}
+	if inQuotes {
+		return nil, errors.New("unterminated quote")
+	}
+	if started {
+		fields = append(fields, current.String())
+	}
+
+	return fields, nil
+}
+
+func unquote(value string) string {
+	if unquoted, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+		return unquoted
+	}
+
+	return value
+}
+
+func validShortcodeName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, char := range name {
+		if !(unicode.IsLetter(char) || char == '_' || (i > 0 && (unicode.IsDigit(char) || char == '-'))) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func excerpt(text string) string {
+	if line, _, ok := strings.Cut(text, "\n"); ok {
+		return line
+	}
+
+	return text
+}
+
+func ExpandShortcodes(documents map[string]Document, shortcodes *Shortcodes) (map[string]Document, error) {
+	result := make(map[string]Document, len(documents))
+	for locale, document := range documents {
+		bodyHTML, err := renderMarkdown(document.BodyMD, document.Options, shortcodes, locale, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s markdown: %w", locale, err)
+		}
+
+		document.BodyHTML = bodyHTML
+		document.Shortcodes = shortcodes
+		result[locale] = document
+	}
+
+	return result, nil
+}
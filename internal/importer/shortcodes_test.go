@@ -0,0 +1,115 @@
+package importer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandShortcodes(t *testing.T) {
+	body := `Watch {{< youtube abc123 >}}
+
+{{< callout type="warning" title="Heads up" >}}
+Prices **change**.
+
+{{< callout >}}Nested{{< /callout >}}
+{{< /callout >}}
+
+{{< product slug="lamp" >}}
+
+Write ` + "`{{</* youtube id */>}}`" + ` to embed a video.`
+
+	shortcodes := NewShortcodes()
+	shortcodes.SetData("product", func(call ShortcodeCall) (any, error) {
+		if call.Get("slug") != "lamp" && call.Get(0) != "lamp" {
+			return nil, errors.New("unexpected slug")
+		}
+
+		return map[string]any{"slug": "lamp", "name": map[string]any{"vi": "Đèn", "en": "Lamp"}, "price": "10"}, nil
+	})
+
+	documents, err := ExpandShortcodes(map[string]Document{
+		"en": {BodyMD: body, Options: DefaultRenderOptions},
+		"vi": {BodyMD: `{{< product "lamp" >}}`, Options: DefaultRenderOptions},
+	}, shortcodes)
+	if err != nil {
+		t.Fatalf("expand failed: %v", err)
+	}
+
+	expected := []string{
+		`<p>Watch <div class="embed embed-youtube"><iframe src="https://www.youtube-nocookie.com/embed/abc123" title="YouTube video"`,
+		`<div class="callout callout-warning"><p class="callout-title">Heads up</p><p>Prices <strong>change</strong>.</p>` + "\n" + `<div class="callout callout-note"><p>Nested</p></div></div>`,
+		`<div class="product-card" data-slug="lamp"><a class="product-card-name" href="/products/lamp">Lamp</a><span class="product-card-price">10</span></div>`,
+		`<code>{{&lt; youtube id &gt;}}</code>`,
+	}
+	for _, fragment := range expected {
+		if !strings.Contains(documents["en"].BodyHTML, fragment) {
+			t.Fatalf("expected %q in:\n%s", fragment, documents["en"].BodyHTML)
+		}
+	}
+	if !strings.Contains(documents["vi"].BodyHTML, ">Đèn</a>") || strings.HasPrefix(documents["vi"].BodyHTML, "<p>") {
+		t.Fatalf("expected the Vietnamese product name as a block, got %s", documents["vi"].BodyHTML)
+	}
+
+	for _, invalid := range []string{"{{< unknown >}}", "{{< /callout >}}", "{{< youtube", `{{< youtube "id >}}`} {
+		if _, err := ExpandShortcodes(map[string]Document{"en": {BodyMD: invalid}}, shortcodes); err == nil {
+			t.Fatalf("expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestShortcodeLocalizedFallsBackToVietnamese(t *testing.T) {
+	call := ShortcodeCall{Locale: "en"}
+
+	if text := call.Localized(map[string]any{"vi": "Đèn"}); text != "Đèn" {
+		t.Fatalf("expected the Vietnamese text, got %q", text)
+	}
+	if text := call.Localized(map[string]string{"vi": "Đèn", "en": ""}); text != "Đèn" {
+		t.Fatalf("expected the Vietnamese text for an empty translation, got %q", text)
+	}
+	if text := call.Localized(map[string]any{"vi": "Đèn", "en": "Lamp"}); text != "Lamp" {
+		t.Fatalf("expected the English text, got %q", text)
+	}
+}
+
+func TestLoadShortcodesFromProjectAndDirectory(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		ProjectFile:              "shortcodes: templates\n",
+		".git/HEAD":              "ref: refs/heads/main\n",
+		"templates/youtube.html": `<lite-youtube videoid="{{ .Get 0 }}"></lite-youtube>`,
+		"templates/badge.html":   `<span class="badge">{{ .Get "text" }}</span>`,
+		"extra/badge.html":       `<mark>{{ .Get "text" }}</mark>`,
+	} {
+		target := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	shortcodes, err := LoadShortcodes(filepath.Join(root, "posts", "post.md"), filepath.Join(root, "extra"))
+	if err != nil {
+		t.Fatalf("failed to load shortcodes: %v", err)
+	}
+
+	documents, err := ExpandShortcodes(map[string]Document{
+		"en": {BodyMD: `{{< youtube abc >}} {{< badge text="<new>" />}} {{< callout >}}Kept{{< /callout >}}`},
+	}, shortcodes)
+	if err != nil {
+		t.Fatalf("expand failed: %v", err)
+	}
+
+	expected := `<p><lite-youtube videoid="abc"></lite-youtube> <mark>&lt;new&gt;</mark> <div class="callout callout-note"><p>Kept</p></div></p>`
+	if documents["en"].BodyHTML != expected {
+		t.Fatalf("expected %s, got %s", expected, documents["en"].BodyHTML)
+	}
+
+	if _, err := LoadShortcodes(filepath.Join(root, "post.md"), filepath.Join(root, "missing")); err == nil {
+		t.Fatal("expected an error for a missing shortcode directory")
+	}
+}
@@ -0,0 +1,106 @@
+// Package mediacache remembers the files uploaded to each site by content
+// hash, so importing the same image again reuses the existing media
+// instead of uploading a duplicate.
+package mediacache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+type Cache struct {
+	path string
+	// Sites maps a base URL to the media URL of each content hash.
+	Sites map[string]map[string]string `json:"sites"`
+}
+
+func pathFromHome(home string) string {
+	return filepath.Join(home, ".config", "geda-cli", "media-cache.json")
+}
+
+// Path is next to the config file.
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return pathFromHome(home), nil
+}
+
+// Load reads the cache at path, which is empty when the file does not
+// exist yet.
+func Load(path string) (*Cache, error) {
+	cache := &Cache{path: path, Sites: map[string]map[string]string{}}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, cache); err != nil {
+		return nil, fmt.Errorf("invalid media cache %s: %w", path, err)
+	}
+	if cache.Sites == nil {
+		cache.Sites = map[string]map[string]string{}
+	}
+
+	return cache, nil
+}
+
+func (c *Cache) Get(baseURL string, hash string) (string, bool) {
+	link, ok := c.Sites[baseURL][hash]
+
+	return link, ok
+}
+
+// Put remembers link for hash. An empty link is ignored, so a bad upload
+// response is never reused.
+func (c *Cache) Put(baseURL string, hash string, link string) {
+	if link == "" {
+		return
+	}
+	if c.Sites[baseURL] == nil {
+		c.Sites[baseURL] = map[string]string{}
+	}
+
+	c.Sites[baseURL][hash] = link
+}
+
+func (c *Cache) Save() error {
+	encoded, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, encoded, 0o600)
+}
+
+// HashFile returns the SHA-256 of the file at path.
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,65 @@
+package mediacache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheRoundTrip(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "geda-cli", "media-cache.json")
+
+	cache, err := Load(cachePath)
+	if err != nil {
+		t.Fatalf("expected a missing cache to load empty: %v", err)
+	}
+	if _, ok := cache.Get("https://geda.vn", "sha256:a"); ok {
+		t.Fatal("expected an empty cache")
+	}
+
+	cache.Put("https://geda.vn", "sha256:a", "https://geda.vn/storage/a.png")
+	cache.Put("https://geda.vn", "sha256:b", "")
+	if err := cache.Save(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	loaded, err := Load(cachePath)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if link, ok := loaded.Get("https://geda.vn", "sha256:a"); !ok || link != "https://geda.vn/storage/a.png" {
+		t.Fatalf("expected the saved link, got %q", link)
+	}
+	if _, ok := loaded.Get("https://geda.vn", "sha256:b"); ok {
+		t.Fatal("expected an empty link not to be cached")
+	}
+	if _, ok := loaded.Get("https://staging.geda.vn", "sha256:a"); ok {
+		t.Fatal("expected links to be kept per site")
+	}
+
+	if err := os.WriteFile(cachePath, []byte("{"), 0o600); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if _, err := Load(cachePath); err == nil {
+		t.Fatal("expected an error for an invalid cache")
+	}
+}
+
+func TestHashFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(filePath, []byte("abc"), 0o600); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	hash, err := HashFile(filePath)
+	if err != nil {
+		t.Fatalf("failed to hash: %v", err)
+	}
+	if hash != "sha256:ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Fatalf("unexpected hash %s", hash)
+	}
+
+	if _, err := HashFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,746 @@
+// Package mockserver is an in-memory stand-in for the geda-web /api/v1 API.
+// It returns the same data/message/error_code shapes the CLI parses and is
+// used by `geda mock serve` and by tests.
+package mockserver
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resources lists the content collections served under /api/v1/<name>.
+var Resources = []string{"posts", "categories", "tags", "pages", "products"}
+
+type User struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password,omitempty"`
+}
+
+type Server struct {
+	mu          sync.Mutex
+	users       []User
+	tokens      map[string]int
+	collections map[string][]map[string]any
+	settings    map[string]any
+	media       map[string][]byte
+	nextID      map[string]int
+	now         func() time.Time
+}
+
+func New() *Server {
+	server := &Server{
+		users: []User{
+			{ID: 1, Name: "Admin", Email: "admin@example.com", Password: "password"},
+		},
+		tokens:      map[string]int{},
+		collections: map[string][]map[string]any{},
+		settings:    map[string]any{},
+		media:       map[string][]byte{},
+		nextID:      map[string]int{},
+		now:         time.Now,
+	}
+
+	for _, resource := range Resources {
+		server.collections[resource] = []map[string]any{}
+		server.nextID[resource] = 1
+	}
+	server.nextID["media"] = 1
+
+	return server
+}
+
+// AddToken registers a bearer token for the user with the given email, so
+// tests can skip the login call.
+func (s *Server) AddToken(token string, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if strings.EqualFold(user.Email, email) {
+			s.tokens[token] = user.ID
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown user %s", email)
+}
+
+// Seed adds records to a collection, assigning ids and timestamps that are
+// missing.
+func (s *Server) Seed(resource string, records []map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.collections[resource]; !ok {
+		return fmt.Errorf("unknown resource %q", resource)
+	}
+
+	for _, record := range records {
+		if getString(record, "slug") == "" {
+			return fmt.Errorf("%s fixture is missing slug", resource)
+		}
+
+		s.insert(resource, record)
+	}
+
+	return nil
+}
+
+// LoadFixtures seeds the server from a directory holding <resource>.json
+// arrays (for example posts.json), settings.json as a key/value object and
+// users.json as an array of users with passwords. Missing files are skipped.
+func (s *Server) LoadFixtures(dir string) error {
+	for _, resource := range Resources {
+		var records []map[string]any
+		found, err := readFixture(filepath.Join(dir, resource+".json"), &records)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+
+		if err := s.Seed(resource, records); err != nil {
+			return err
+		}
+	}
+
+	settings := map[string]any{}
+	if _, err := readFixture(filepath.Join(dir, "settings.json"), &settings); err != nil {
+		return err
+	}
+
+	var users []User
+	if _, err := readFixture(filepath.Join(dir, "users.json"), &users); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, value := range settings {
+		s.settings[key] = value
+	}
+	for _, user := range users {
+		if user.ID == 0 {
+			user.ID = len(s.users) + 1
+		}
+		s.users = append(s.users, user)
+	}
+
+	return nil
+}
+
+func readFixture(filePath string, target any) (bool, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if err := json.Unmarshal(data, target); err != nil {
+		return false, fmt.Errorf("invalid fixture %s: %w", filePath, err)
+	}
+
+	return true, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if strings.HasPrefix(r.URL.Path, "/storage/") {
+		s.serveMedia(w, r)
+
+		return
+	}
+
+	route := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1"), "/")
+	if route == r.URL.Path || route == "" {
+		writeError(w, http.StatusNotFound, "Not found.", "not_found")
+
+		return
+	}
+
+	segments := strings.Split(route, "/")
+
+	switch {
+	case route == "health" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"status": "ok", "app": "GEDA", "environment": "mock"})
+	case route == "auth/login" && r.Method == http.MethodPost:
+		s.handleLogin(w, r)
+	case segments[0] == "auth":
+		user, ok := s.authenticate(w, r)
+		if !ok {
+			return
+		}
+
+		switch {
+		case route == "auth/me" && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"data": publicUser(user)})
+		case route == "auth/logout" && r.Method == http.MethodPost:
+			delete(s.tokens, bearerToken(r))
+			writeJSON(w, http.StatusOK, map[string]any{"message": "Logged out successfully."})
+		default:
+			writeError(w, http.StatusNotFound, "Not found.", "not_found")
+		}
+	case segments[0] == "settings":
+		if _, ok := s.authenticate(w, r); !ok {
+			return
+		}
+
+		s.handleSettings(w, r, segments[1:])
+	case segments[0] == "media" && len(segments) == 1 && r.Method == http.MethodPost:
+		if _, ok := s.authenticate(w, r); !ok {
+			return
+		}
+
+		s.handleUpload(w, r)
+	case s.collections[segments[0]] != nil && len(segments) <= 2:
+		if _, ok := s.authenticate(w, r); !ok {
+			return
+		}
+
+		slug := ""
+		if len(segments) == 2 {
+			slug = segments[1]
+		}
+
+		s.handleResource(w, r, segments[0], slug)
+	default:
+		writeError(w, http.StatusNotFound, "Not found.", "not_found")
+	}
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var payload map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON body.", "invalid_json")
+
+		return
+	}
+
+	email := getString(payload, "email")
+	password := getString(payload, "password")
+	fieldErrors := map[string][]string{}
+	if email == "" {
+		fieldErrors["email"] = []string{"The email field is required."}
+	}
+	if password == "" {
+		fieldErrors["password"] = []string{"The password field is required."}
+	}
+	if len(fieldErrors) > 0 {
+		writeValidation(w, fieldErrors)
+
+		return
+	}
+
+	for _, user := range s.users {
+		if strings.EqualFold(user.Email, email) && user.Password == password {
+			token := fmt.Sprintf("%d|%s", user.ID, randomHex(20))
+			s.tokens[token] = user.ID
+
+			writeJSON(w, http.StatusOK, map[string]any{
+				"access_token": token,
+				"token_type":   "Bearer",
+				"user":         publicUser(user),
+			})
+
+			return
+		}
+	}
+
+	writeValidation(w, map[string][]string{"email": {"These credentials do not match our records."}})
+}
+
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) (User, bool) {
+	userID, ok := s.tokens[bearerToken(r)]
+	if ok {
+		for _, user := range s.users {
+			if user.ID == userID {
+				return user, true
+			}
+		}
+	}
+
+	writeError(w, http.StatusUnauthorized, "Unauthenticated.", "unauthenticated")
+
+	return User{}, false
+}
+
+func (s *Server) handleResource(w http.ResponseWriter, r *http.Request, resource string, slug string) {
+	switch {
+	case slug == "" && r.Method == http.MethodGet:
+		s.handleList(w, r, resource)
+	case slug == "" && r.Method == http.MethodPost:
+		payload, ok := decodePayload(w, r)
+		if !ok {
+			return
+		}
+
+		newSlug := getString(payload, "slug")
+		if newSlug == "" {
+			writeValidation(w, map[string][]string{"slug": {"The slug field is required."}})
+
+			return
+		}
+		if s.find(resource, newSlug) >= 0 {
+			writeValidation(w, map[string][]string{"slug": {"The slug has already been taken."}})
+
+			return
+		}
+
+		record := s.insert(resource, payload)
+		writeJSON(w, http.StatusCreated, map[string]any{
+			"message": singularTitle(resource) + " created successfully.",
+			"data":    record,
+		})
+	case slug != "" && r.Method == http.MethodGet:
+		index := s.find(resource, slug)
+		if index < 0 {
+			writeError(w, http.StatusNotFound, singularTitle(resource)+" not found.", "not_found")
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{"data": s.collections[resource][index]})
+	case slug != "" && r.Method == http.MethodPut:
+		index := s.find(resource, slug)
+		if index < 0 {
+			writeError(w, http.StatusNotFound, singularTitle(resource)+" not found.", "not_found")
+
+			return
+		}
+
+		payload, ok := decodePayload(w, r)
+		if !ok {
+			return
+		}
+
+		if newSlug := getString(payload, "slug"); newSlug != "" && newSlug != slug && s.find(resource, newSlug) >= 0 {
+			writeValidation(w, map[string][]string{"slug": {"The slug has already been taken."}})
+
+			return
+		}
+
+		record := s.collections[resource][index]
+		if !unmodifiedSince(r, record) {
+			writeError(w, http.StatusPreconditionFailed, singularTitle(resource)+" was modified.", "precondition_failed")
+
+			return
+		}
+
+		for key, value := range payload {
+			if key == "id" || key == "created_at" || key == "updated_at" {
+				continue
+			}
+
+			record[key] = value
+		}
+		record["updated_at"] = s.timestamp()
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"message": singularTitle(resource) + " updated successfully.",
+			"data":    record,
+		})
+	case slug != "" && r.Method == http.MethodDelete:
+		index := s.find(resource, slug)
+		if index < 0 {
+			writeError(w, http.StatusNotFound, singularTitle(resource)+" not found.", "not_found")
+
+			return
+		}
+
+		if !unmodifiedSince(r, s.collections[resource][index]) {
+			writeError(w, http.StatusPreconditionFailed, singularTitle(resource)+" was modified.", "precondition_failed")
+
+			return
+		}
+
+		s.collections[resource] = append(s.collections[resource][:index], s.collections[resource][index+1:]...)
+		writeJSON(w, http.StatusOK, map[string]any{
+			"message": singularTitle(resource) + " deleted successfully.",
+		})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.", "method_not_allowed")
+	}
+}
+
+// handleList filters by search, status and type, then paginates the way
+// Laravel's LengthAwarePaginator does.
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request, resource string) {
+	query := r.URL.Query()
+	search := strings.ToLower(query.Get("search"))
+	status := query.Get("status")
+	typeFilter := query.Get("type")
+
+	items := []map[string]any{}
+	for _, record := range s.collections[resource] {
+		if status != "" && getString(record, "status") != status {
+			continue
+		}
+		if typeFilter != "" && getString(record, "type") != typeFilter {
+			continue
+		}
+		if search != "" && !recordMatches(record, search) {
+			continue
+		}
+
+		items = append(items, record)
+	}
+
+	perPage := positiveInt(query.Get("per_page"), 15)
+	page := positiveInt(query.Get("page"), 1)
+	total := len(items)
+	lastPage := max(1, (total+perPage-1)/perPage)
+
+	from := min((page-1)*perPage, total)
+	to := min(from+perPage, total)
+
+	pageURL := func(number int) any {
+		if number < 1 || number > lastPage {
+			return nil
+		}
+
+		values := r.URL.Query()
+		values.Set("page", strconv.Itoa(number))
+
+		return requestBaseURL(r) + r.URL.Path + "?" + values.Encode()
+	}
+
+	meta := map[string]any{
+		"current_page": page,
+		"last_page":    lastPage,
+		"per_page":     perPage,
+		"total":        total,
+		"from":         nil,
+		"to":           nil,
+	}
+	if from < to {
+		meta["from"] = from + 1
+		meta["to"] = to
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": items[from:to],
+		"links": map[string]any{
+			"first": pageURL(1),
+			"last":  pageURL(lastPage),
+			"prev":  pageURL(page - 1),
+			"next":  pageURL(page + 1),
+		},
+		"meta": meta,
+	})
+}
+
+func (s *Server) handleSettings(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		keys := make([]string, 0, len(s.settings))
+		for key := range s.settings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		data := make([]map[string]any, 0, len(keys))
+		for _, key := range keys {
+			data = append(data, map[string]any{"key": key, "value": s.settings[key]})
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{"data": data})
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		value, ok := s.settings[segments[0]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Setting not found.", "not_found")
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"key": segments[0], "value": value}})
+	case len(segments) == 1 && r.Method == http.MethodPut:
+		payload, ok := decodePayload(w, r)
+		if !ok {
+			return
+		}
+
+		value, ok := payload["value"]
+		if !ok {
+			writeValidation(w, map[string][]string{"value": {"The value field is present."}})
+
+			return
+		}
+
+		s.settings[segments[0]] = value
+		writeJSON(w, http.StatusOK, map[string]any{
+			"message": "Setting updated successfully.",
+			"data":    map[string]any{"key": segments[0], "value": value},
+		})
+	default:
+		writeError(w, http.StatusNotFound, "Not found.", "not_found")
+	}
+}
+
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid multipart body.", "invalid_multipart")
+
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeValidation(w, map[string][]string{"file": {"The file field is required."}})
+
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Failed to read upload.", "invalid_multipart")
+
+		return
+	}
+
+	id := s.nextID["media"]
+	s.nextID["media"]++
+
+	now := s.now().UTC()
+	mediaPath := path.Join("media", now.Format("2006"), now.Format("01"), fmt.Sprintf("%d-%s", id, path.Base(header.Filename)))
+	s.media[mediaPath] = content
+
+	altText := map[string]any{}
+	for key, values := range r.MultipartForm.Value {
+		if locale, ok := strings.CutPrefix(key, "alt_text["); ok && len(values) > 0 {
+			altText[strings.TrimSuffix(locale, "]")] = values[0]
+		}
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"message": "Media uploaded successfully.",
+		"data": map[string]any{
+			"id":       id,
+			"url":      requestBaseURL(r) + "/storage/" + mediaPath,
+			"path":     mediaPath,
+			"alt_text": altText,
+			"size":     len(content),
+		},
+	})
+}
+
+func (s *Server) serveMedia(w http.ResponseWriter, r *http.Request) {
+	content, ok := s.media[strings.TrimPrefix(r.URL.Path, "/storage/")]
+	if !ok || r.Method != http.MethodGet {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", http.DetectContentType(content))
+	_, _ = w.Write(content)
+}
+
+func (s *Server) insert(resource string, payload map[string]any) map[string]any {
+	record := map[string]any{}
+	for key, value := range payload {
+		record[key] = value
+	}
+
+	if _, ok := record["id"]; ok {
+		if id, isNumber := record["id"].(float64); isNumber && int(id) >= s.nextID[resource] {
+			s.nextID[resource] = int(id) + 1
+		}
+	} else {
+		record["id"] = s.nextID[resource]
+		s.nextID[resource]++
+	}
+
+	now := s.timestamp()
+	if _, ok := record["created_at"]; !ok {
+		record["created_at"] = now
+	}
+	if _, ok := record["updated_at"]; !ok {
+		record["updated_at"] = now
+	}
+
+	s.collections[resource] = append(s.collections[resource], record)
+
+	return record
+}
+
+func (s *Server) find(resource string, slug string) int {
+	for i, record := range s.collections[resource] {
+		if getString(record, "slug") == slug {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (s *Server) timestamp() string {
+	return s.now().UTC().Format(time.RFC3339Nano)
+}
+
+// unmodifiedSince reports whether record meets the request's
+// If-Unmodified-Since precondition. HTTP dates have whole seconds, so
+// updated_at is compared to the second. A missing or invalid header passes.
+func unmodifiedSince(r *http.Request, record map[string]any) bool {
+	since, err := http.ParseTime(r.Header.Get("If-Unmodified-Since"))
+	if err != nil {
+		return true
+	}
+
+	updatedAt, err := time.Parse(time.RFC3339Nano, getString(record, "updated_at"))
+	if err != nil {
+		return true
+	}
+
+	return !updatedAt.Truncate(time.Second).After(since)
+}
+
+func decodePayload(w http.ResponseWriter, r *http.Request) (map[string]any, bool) {
+	payload := map[string]any{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON body.", "invalid_json")
+
+		return nil, false
+	}
+
+	return payload, true
+}
+
+func recordMatches(record map[string]any, search string) bool {
+	if strings.Contains(strings.ToLower(getString(record, "slug")), search) {
+		return true
+	}
+
+	for _, field := range []string{"title", "name"} {
+		switch value := record[field].(type) {
+		case string:
+			if strings.Contains(strings.ToLower(value), search) {
+				return true
+			}
+		case map[string]any:
+			for _, localized := range value {
+				if text, ok := localized.(string); ok && strings.Contains(strings.ToLower(text), search) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func writeError(w http.ResponseWriter, status int, message string, code string) {
+	writeJSON(w, status, map[string]any{
+		"message":    message,
+		"error_code": code,
+	})
+}
+
+// writeValidation mirrors Laravel's 422 response.
+func writeValidation(w http.ResponseWriter, fieldErrors map[string][]string) {
+	fields := make([]string, 0, len(fieldErrors))
+	for field := range fieldErrors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	message := fieldErrors[fields[0]][0]
+	if extra := len(fields) - 1; extra > 0 {
+		message = fmt.Sprintf("%s (and %d more error", message, extra)
+		if extra > 1 {
+			message += "s"
+		}
+		message += ")"
+	}
+
+	writeJSON(w, http.StatusUnprocessableEntity, map[string]any{
+		"message":    message,
+		"error_code": "validation_failed",
+		"errors":     fieldErrors,
+	})
+}
+
+func publicUser(user User) map[string]any {
+	return map[string]any{
+		"id":    user.ID,
+		"name":  user.Name,
+		"email": user.Email,
+	}
+}
+
+func bearerToken(r *http.Request) string {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	return strings.TrimSpace(token)
+}
+
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host
+}
+
+func positiveInt(value string, fallback int) int {
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 1 {
+		return fallback
+	}
+
+	return parsed
+}
+
+func getString(payload map[string]any, key string) string {
+	value, _ := payload[key].(string)
+
+	return value
+}
+
+func singularTitle(resource string) string {
+	switch resource {
+	case "categories":
+		return "Category"
+	default:
+		name := strings.TrimSuffix(resource, "s")
+
+		return strings.ToUpper(name[:1]) + name[1:]
+	}
+}
+
+func randomHex(size int) string {
+	buffer := make([]byte, size)
+	_, _ = rand.Read(buffer)
+
+	return hex.EncodeToString(buffer)
+}
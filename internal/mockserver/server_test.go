@@ -0,0 +1,184 @@
+package mockserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoginAndAuthenticatedRoutes(t *testing.T) {
+	server := httptest.NewServer(New())
+	defer server.Close()
+
+	status, body := request(t, server, http.MethodGet, "/api/v1/auth/me", "", nil)
+	if status != http.StatusUnauthorized || body["error_code"] != "unauthenticated" {
+		t.Fatalf("expected 401 unauthenticated, got %d %#v", status, body)
+	}
+
+	status, body = request(t, server, http.MethodPost, "/api/v1/auth/login", "", map[string]any{
+		"email":    "admin@example.com",
+		"password": "wrong",
+	})
+	if status != http.StatusUnprocessableEntity || body["errors"] == nil {
+		t.Fatalf("expected 422 with errors, got %d %#v", status, body)
+	}
+
+	status, body = request(t, server, http.MethodPost, "/api/v1/auth/login", "", map[string]any{
+		"email":    "admin@example.com",
+		"password": "password",
+	})
+	token, _ := body["access_token"].(string)
+	if status != http.StatusOK || token == "" {
+		t.Fatalf("expected access token, got %d %#v", status, body)
+	}
+
+	status, body = request(t, server, http.MethodGet, "/api/v1/auth/me", token, nil)
+	if status != http.StatusOK || body["data"].(map[string]any)["email"] != "admin@example.com" {
+		t.Fatalf("expected current user, got %d %#v", status, body)
+	}
+
+	status, _ = request(t, server, http.MethodPost, "/api/v1/auth/logout", token, map[string]any{})
+	if status != http.StatusOK {
+		t.Fatalf("expected logout to succeed, got %d", status)
+	}
+
+	status, _ = request(t, server, http.MethodGet, "/api/v1/auth/me", token, nil)
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected token to be revoked, got %d", status)
+	}
+}
+
+func TestResourceCRUDAndPagination(t *testing.T) {
+	mock := New()
+	if err := mock.AddToken("token", "admin@example.com"); err != nil {
+		t.Fatalf("failed to add token: %v", err)
+	}
+
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	for _, slug := range []string{"one", "two", "three"} {
+		status, body := request(t, server, http.MethodPost, "/api/v1/posts", "token", map[string]any{
+			"slug":   slug,
+			"title":  map[string]any{"vi": "Bai " + slug, "en": "Post " + slug},
+			"status": "draft",
+		})
+		if status != http.StatusCreated || body["data"].(map[string]any)["id"] == nil {
+			t.Fatalf("expected post to be created, got %d %#v", status, body)
+		}
+	}
+
+	status, body := request(t, server, http.MethodPost, "/api/v1/posts", "token", map[string]any{"slug": "one"})
+	if status != http.StatusUnprocessableEntity || body["error_code"] != "validation_failed" {
+		t.Fatalf("expected duplicate slug to fail validation, got %d %#v", status, body)
+	}
+
+	status, body = request(t, server, http.MethodPut, "/api/v1/posts/two", "token", map[string]any{"status": "published"})
+	if status != http.StatusOK || body["data"].(map[string]any)["status"] != "published" {
+		t.Fatalf("expected post to be updated, got %d %#v", status, body)
+	}
+
+	status, body = request(t, server, http.MethodGet, "/api/v1/posts?per_page=2&page=1", "token", nil)
+	if status != http.StatusOK {
+		t.Fatalf("expected list to succeed, got %d", status)
+	}
+	meta := body["meta"].(map[string]any)
+	if meta["last_page"] != float64(2) || meta["total"] != float64(3) || len(body["data"].([]any)) != 2 {
+		t.Fatalf("unexpected pagination %#v", body)
+	}
+	if body["links"].(map[string]any)["next"] == nil {
+		t.Fatalf("expected next link, got %#v", body["links"])
+	}
+
+	status, body = request(t, server, http.MethodGet, "/api/v1/posts?status=published&search=post+two", "token", nil)
+	if status != http.StatusOK || len(body["data"].([]any)) != 1 {
+		t.Fatalf("expected filtered list with one post, got %d %#v", status, body)
+	}
+
+	status, _ = request(t, server, http.MethodDelete, "/api/v1/posts/one", "token", nil)
+	if status != http.StatusOK {
+		t.Fatalf("expected delete to succeed, got %d", status)
+	}
+
+	status, body = request(t, server, http.MethodGet, "/api/v1/posts/one", "token", nil)
+	if status != http.StatusNotFound || body["error_code"] != "not_found" {
+		t.Fatalf("expected 404 after delete, got %d %#v", status, body)
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, filepath.Join(dir, "categories.json"), `[{"id": 7, "slug": "tin-tuc", "name": {"vi": "Tin tuc", "en": "News"}}]`)
+	writeFixture(t, filepath.Join(dir, "settings.json"), `{"site_name": "GEDA"}`)
+	writeFixture(t, filepath.Join(dir, "users.json"), `[{"name": "Editor", "email": "editor@example.com", "password": "secret"}]`)
+
+	mock := New()
+	if err := mock.LoadFixtures(dir); err != nil {
+		t.Fatalf("failed to load fixtures: %v", err)
+	}
+	if err := mock.AddToken("token", "editor@example.com"); err != nil {
+		t.Fatalf("expected fixture user to exist: %v", err)
+	}
+
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	status, body := request(t, server, http.MethodGet, "/api/v1/categories/tin-tuc", "token", nil)
+	if status != http.StatusOK || body["data"].(map[string]any)["id"] != float64(7) {
+		t.Fatalf("expected seeded category, got %d %#v", status, body)
+	}
+
+	status, body = request(t, server, http.MethodPost, "/api/v1/categories", "token", map[string]any{"slug": "blog"})
+	if status != http.StatusCreated || body["data"].(map[string]any)["id"] != float64(8) {
+		t.Fatalf("expected ids to continue after fixtures, got %d %#v", status, body)
+	}
+
+	status, body = request(t, server, http.MethodGet, "/api/v1/settings/site_name", "token", nil)
+	if status != http.StatusOK || body["data"].(map[string]any)["value"] != "GEDA" {
+		t.Fatalf("expected seeded setting, got %d %#v", status, body)
+	}
+}
+
+func request(t *testing.T, server *httptest.Server, method string, path string, token string, payload any) (int, map[string]any) {
+	t.Helper()
+
+	var body bytes.Buffer
+	if payload != nil {
+		if err := json.NewEncoder(&body).Encode(payload); err != nil {
+			t.Fatalf("failed to encode payload: %v", err)
+		}
+	}
+
+	req, err := http.NewRequest(method, server.URL+path, &body)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	decoded := map[string]any{}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	return resp.StatusCode, decoded
+}
+
+func writeFixture(t *testing.T, filePath string, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+}
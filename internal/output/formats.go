@@ -0,0 +1,167 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+func printYAML(w io.Writer, data any) error {
+	payload, err := yaml.Marshal(toGeneric(data))
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(payload)
+
+	return err
+}
+
+// printDelimited writes list items as CSV or TSV rows with a header line.
+// TSV values are never quoted; tabs and newlines inside them become spaces.
+func printDelimited(w io.Writer, data any, opts Options) error {
+	rows, _ := tableRows(data)
+
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = defaultColumns[opts.Resource]
+	}
+	if len(columns) == 0 {
+		columns = scalarKeys(rows)
+	}
+
+	records := make([][]string, 0, len(rows)+1)
+	records = append(records, columns)
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = fieldText(lookup(row, column), opts.Locale)
+		}
+		records = append(records, record)
+	}
+
+	if opts.Format == FormatTSV {
+		for _, record := range records {
+			for i, value := range record {
+				record[i] = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(value)
+			}
+
+			if _, err := fmt.Fprintln(w, strings.Join(record, "\t")); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+
+	return writer.Error()
+}
+
+// fieldText is cellText without folding whitespace, since CSV can carry
+// multi-line values.
+func fieldText(value any, locale string) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+
+	if item, ok := value.(map[string]any); ok {
+		for _, key := range []string{locale, fallbackLocale} {
+			if text, ok := item[key].(string); ok && key != "" {
+				return text
+			}
+		}
+	}
+
+	return cellText(value, locale)
+}
+
+// ParseTemplate parses a --template value. Besides the text/template
+// builtins it provides json, which encodes a value as compact JSON, and
+// tr, which picks the --locale translation of a bilingual field. A key the
+// item does not have is an error rather than "<no value>" in the output.
+func ParseTemplate(text string, locale string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("template is empty")
+	}
+
+	return template.New("output").Option("missingkey=error").Funcs(template.FuncMap{
+		"json": func(value any) (string, error) {
+			payload, err := json.Marshal(value)
+
+			return string(payload), err
+		},
+		"tr": func(value any) string {
+			return fieldText(value, locale)
+		},
+	}).Parse(text)
+}
+
+// printTemplate executes the template once per list item, or once for a
+// single record, writing a newline after each execution.
+func printTemplate(w io.Writer, data any, opts Options) error {
+	tmpl, err := ParseTemplate(opts.Template, opts.Locale)
+	if err != nil {
+		return err
+	}
+
+	response := toGeneric(data)
+
+	var items []any
+	switch typed := response.(type) {
+	case map[string]any:
+		switch inner := typed["data"].(type) {
+		case []any:
+			items = inner
+		case map[string]any:
+			items = []any{inner}
+		default:
+			items = []any{typed}
+		}
+	default:
+		items = []any{typed}
+	}
+
+	for _, item := range items {
+		if err := tmpl.Execute(w, item); err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// toGeneric converts typed values to the maps and slices encoding/json
+// produces, so every format sees the same shape.
+func toGeneric(data any) any {
+	switch data.(type) {
+	case map[string]any, []any:
+		return data
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+
+	var result any
+	if err := json.Unmarshal(encoded, &result); err != nil {
+		return data
+	}
+
+	return result
+}
@@ -0,0 +1,101 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func listResponse() map[string]any {
+	return map[string]any{
+		"data": []any{
+			map[string]any{
+				"id":    float64(1),
+				"slug":  "xin-chao",
+				"title": map[string]any{"vi": "Xin chào", "en": "Hello, world"},
+			},
+			map[string]any{
+				"id":    float64(2),
+				"slug":  "nhieu-dong",
+				"title": map[string]any{"vi": "Dòng một\tDòng hai"},
+			},
+		},
+		"meta": map[string]any{"total": float64(2)},
+	}
+}
+
+func TestPrintYAML(t *testing.T) {
+	var out bytes.Buffer
+	if err := printYAML(&out, map[string]any{"data": map[string]any{"slug": "xin-chao", "id": float64(3)}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "data:\n    id: 3\n    slug: xin-chao\n"
+	if out.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestPrintCSVFlattensData(t *testing.T) {
+	var out bytes.Buffer
+	opts := Options{Format: FormatCSV, Columns: []string{"id", "slug", "title"}, Locale: "en"}
+	if err := printDelimited(&out, listResponse(), opts); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "id,slug,title\n1,xin-chao,\"Hello, world\"\n2,nhieu-dong,Dòng một\tDòng hai\n"
+	if out.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestPrintTSVReplacesTabs(t *testing.T) {
+	var out bytes.Buffer
+	opts := Options{Format: FormatTSV, Columns: []string{"slug", "title.vi"}}
+	if err := printDelimited(&out, listResponse(), opts); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "slug\ttitle.vi\nxin-chao\tXin chào\nnhieu-dong\tDòng một Dòng hai\n"
+	if out.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestPrintTemplateRunsPerItem(t *testing.T) {
+	var out bytes.Buffer
+	opts := Options{Format: FormatTemplate, Template: "{{.slug}} {{tr .title}}", Locale: "en"}
+	if err := printTemplate(&out, listResponse(), opts); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "xin-chao Hello, world\nnhieu-dong Dòng một\tDòng hai\n"
+	if out.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, out.String())
+	}
+
+	out.Reset()
+	opts.Template = "{{.url}}"
+	if err := printTemplate(&out, map[string]any{"data": map[string]any{"url": "https://geda.vn/a.png"}}, opts); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.String() != "https://geda.vn/a.png\n" {
+		t.Fatalf("expected single record output, got %q", out.String())
+	}
+
+	out.Reset()
+	opts.Template = "{{.slug}} {{.missing}}"
+	err := printTemplate(&out, listResponse(), opts)
+	if err == nil || !strings.Contains(err.Error(), `"missing"`) || strings.Contains(out.String(), "<no value>") {
+		t.Fatalf("expected a missing key to fail, got %v and %q", err, out.String())
+	}
+}
+
+func TestParseTemplateRejectsInvalidTemplates(t *testing.T) {
+	if _, err := ParseTemplate("{{.slug", "vi"); err == nil {
+		t.Fatal("expected parse error")
+	}
+	if _, err := ParseTemplate("  ", "vi"); err == nil {
+		t.Fatal("expected empty template to fail")
+	}
+}
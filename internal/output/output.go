@@ -4,10 +4,81 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 )
 
-func Print(data any, human bool) error {
-	if human {
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatTable    Format = "table"
+	FormatYAML     Format = "yaml"
+	FormatCSV      Format = "csv"
+	FormatTSV      Format = "tsv"
+	FormatTemplate Format = "template"
+)
+
+// Formats lists the values accepted by --output.
+var Formats = []Format{FormatJSON, FormatTable, FormatYAML, FormatCSV, FormatTSV, FormatTemplate}
+
+func ParseFormat(value string) (Format, error) {
+	for _, format := range Formats {
+		if strings.EqualFold(value, string(format)) {
+			return format, nil
+		}
+	}
+
+	names := make([]string, len(Formats))
+	for i, format := range Formats {
+		names[i] = string(format)
+	}
+
+	return "", fmt.Errorf("unknown output format %q, expected one of %s", value, strings.Join(names, ", "))
+}
+
+// Options controls how Print renders a response.
+type Options struct {
+	Human  bool
+	Format Format
+	// Columns are dotted field paths shown by the table, CSV and TSV
+	// formats. When empty the resource's default columns are used.
+	Columns []string
+	// Locale picks the language shown for bilingual fields.
+	Locale string
+	// Template is the text/template applied to each item by the template
+	// format.
+	Template string
+	// Query selects part of the response before it is printed, see Query.
+	Query string
+	// Raw prints selected strings without JSON quotes.
+	Raw bool
+	// Resource names the kind of record being printed, such as "post".
+	Resource string
+}
+
+func Print(data any, opts Options) error {
+	data, err := selectQuery(data, opts)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case FormatTable:
+		return printTable(os.Stdout, data, opts)
+	case FormatYAML:
+		return printYAML(os.Stdout, data)
+	case FormatCSV, FormatTSV:
+		return printDelimited(os.Stdout, data, opts)
+	case FormatTemplate:
+		return printTemplate(os.Stdout, data, opts)
+	}
+
+	if opts.Raw {
+		return printRaw(os.Stdout, data, opts.Human)
+	}
+
+	if opts.Human {
 		payload, err := json.MarshalIndent(data, "", "  ")
 		if err != nil {
 			return err
@@ -60,3 +131,68 @@ func PrintError(message string, code string, details any, human bool) {
 
 	fmt.Fprintln(os.Stderr, string(encoded))
 }
+
+// PrintLine writes data as a single line of compact JSON, for NDJSON
+// streams. It ignores human mode so the stream stays machine-readable, but
+// applies opts.Query and opts.Raw.
+func PrintLine(data any, opts Options) error {
+	data, err := selectQuery(data, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.Raw {
+		return printRaw(os.Stdout, data, false)
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(os.Stdout, string(payload))
+
+	return err
+}
+
+func selectQuery(data any, opts Options) (any, error) {
+	if opts.Query == "" {
+		return data, nil
+	}
+
+	query, err := ParseQuery(opts.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	return query.Eval(data)
+}
+
+// PrintFieldErrors reports a validation failure. Human mode prints one line
+// per invalid field after the message; JSON mode adds the fields to details
+// under "fields".
+func PrintFieldErrors(message string, code string, fields map[string][]string, details map[string]any, human bool) {
+	if !human {
+		if details == nil {
+			details = map[string]any{}
+		}
+		details["fields"] = fields
+		PrintError(message, code, details, false)
+
+		return
+	}
+
+	PrintError(message, code, nil, true)
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, message := range fields[name] {
+			fmt.Fprintf(os.Stderr, "  %s: %s\n", name, message)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Query selects part of a response with a small subset of jq paths:
+//
+//	data.url            fields, with an optional leading dot
+//	data[0].slug        array indexes, negative ones count from the end
+//	data[].slug         [] applies the rest of the path to every item
+//	data."meta-title"   quoted field names
+//
+// Missing fields and out-of-range indexes select null, as in jq.
+type Query struct {
+	expr  string
+	steps []queryStep
+}
+
+type queryStep struct {
+	field   string
+	index   int
+	isIndex bool
+	iterate bool
+}
+
+func ParseQuery(expr string) (Query, error) {
+	query := Query{expr: expr}
+
+	rest := strings.TrimSpace(expr)
+	rest = strings.TrimPrefix(rest, ".")
+	if rest == "" {
+		return query, nil
+	}
+
+	for rest != "" {
+		switch {
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return query, fmt.Errorf("unclosed [ in query %q", expr)
+			}
+
+			inner := strings.TrimSpace(rest[1:end])
+			switch {
+			case inner == "":
+				query.steps = append(query.steps, queryStep{iterate: true})
+			case strings.HasPrefix(inner, `"`):
+				field, err := strconv.Unquote(inner)
+				if err != nil {
+					return query, fmt.Errorf("invalid field %s in query %q", inner, expr)
+				}
+				query.steps = append(query.steps, queryStep{field: field})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return query, fmt.Errorf("invalid index [%s] in query %q", inner, expr)
+				}
+				query.steps = append(query.steps, queryStep{index: index, isIndex: true})
+			}
+
+			rest = rest[end+1:]
+		case rest[0] == '"':
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return query, fmt.Errorf("unclosed quote in query %q", expr)
+			}
+
+			query.steps = append(query.steps, queryStep{field: rest[1 : end+1]})
+			rest = rest[end+2:]
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return query, fmt.Errorf("empty field name in query %q", expr)
+			}
+
+			query.steps = append(query.steps, queryStep{field: rest[:end]})
+			rest = rest[end:]
+		}
+
+		if strings.HasPrefix(rest, ".") {
+			rest = rest[1:]
+			if rest == "" {
+				return query, fmt.Errorf("query %q ends with a dot", expr)
+			}
+		} else if rest != "" && rest[0] != '[' {
+			return query, fmt.Errorf("unexpected %q in query %q", rest, expr)
+		}
+	}
+
+	return query, nil
+}
+
+// Eval applies the query to data. Once the path iterates with [], the result
+// is the list of values selected from every item.
+func (q Query) Eval(data any) (any, error) {
+	values := []any{toGeneric(data)}
+	projected := false
+
+	for _, step := range q.steps {
+		next := make([]any, 0, len(values))
+
+		for _, value := range values {
+			switch {
+			case step.iterate:
+				switch typed := value.(type) {
+				case []any:
+					next = append(next, typed...)
+				case map[string]any:
+					for _, key := range sortedKeys(typed) {
+						next = append(next, typed[key])
+					}
+				case nil:
+				default:
+					return nil, fmt.Errorf("cannot iterate over %s in query %q", kind(value), q.expr)
+				}
+			case step.isIndex:
+				items, ok := value.([]any)
+				if !ok {
+					if value != nil {
+						return nil, fmt.Errorf("cannot index %s with [%d] in query %q", kind(value), step.index, q.expr)
+					}
+					next = append(next, nil)
+
+					continue
+				}
+
+				index := step.index
+				if index < 0 {
+					index += len(items)
+				}
+				if index < 0 || index >= len(items) {
+					next = append(next, nil)
+
+					continue
+				}
+				next = append(next, items[index])
+			default:
+				object, ok := value.(map[string]any)
+				if !ok && value != nil {
+					return nil, fmt.Errorf("cannot select %q from %s in query %q", step.field, kind(value), q.expr)
+				}
+				next = append(next, object[step.field])
+			}
+		}
+
+		values = next
+		projected = projected || step.iterate
+	}
+
+	if projected {
+		return values, nil
+	}
+
+	return values[0], nil
+}
+
+// printRaw writes strings without quotes and lists of scalars one per line,
+// like jq -r. Objects and other lists are still printed as JSON.
+func printRaw(w io.Writer, value any, human bool) error {
+	if items, ok := value.([]any); ok && allScalars(items) {
+		for _, item := range items {
+			if err := printRaw(w, item, human); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if text, ok := value.(string); ok {
+		_, err := fmt.Fprintln(w, text)
+
+		return err
+	}
+
+	var payload []byte
+	var err error
+	if human {
+		payload, err = json.MarshalIndent(value, "", "  ")
+	} else {
+		payload, err = json.Marshal(value)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(payload))
+
+	return err
+}
+
+func allScalars(items []any) bool {
+	for _, item := range items {
+		switch item.(type) {
+		case map[string]any, []any:
+			return false
+		}
+	}
+
+	return true
+}
+
+func kind(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	default:
+		return "null"
+	}
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
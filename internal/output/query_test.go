@@ -0,0 +1,80 @@
+package output
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestQueryEval(t *testing.T) {
+	response := map[string]any{
+		"data": []any{
+			map[string]any{"slug": "a", "tags": []any{map[string]any{"slug": "go"}}, "meta-title": "A"},
+			map[string]any{"slug": "b", "tags": []any{map[string]any{"slug": "cli"}, map[string]any{"slug": "web"}}},
+		},
+		"meta": map[string]any{"total": float64(2)},
+	}
+
+	tests := []struct {
+		expr     string
+		expected any
+	}{
+		{"", response},
+		{".", response},
+		{"meta.total", float64(2)},
+		{".meta.total", float64(2)},
+		{"data[0].slug", "a"},
+		{"data[-1].slug", "b"},
+		{"data[5].slug", nil},
+		{"data[].slug", []any{"a", "b"}},
+		{"data[].tags[].slug", []any{"go", "cli", "web"}},
+		{`data[0]."meta-title"`, "A"},
+		{`data[0]["meta-title"]`, "A"},
+		{"missing.field", nil},
+	}
+
+	for _, test := range tests {
+		query, err := ParseQuery(test.expr)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", test.expr, err)
+		}
+
+		result, err := query.Eval(response)
+		if err != nil {
+			t.Fatalf("failed to evaluate %q: %v", test.expr, err)
+		}
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Fatalf("%q: expected %#v, got %#v", test.expr, test.expected, result)
+		}
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	for _, expr := range []string{"data[", "data[x]", "data.", "data..slug", `data."slug`} {
+		if _, err := ParseQuery(expr); err == nil {
+			t.Fatalf("expected %q to fail to parse", expr)
+		}
+	}
+
+	query, err := ParseQuery("data.slug.length")
+	if err != nil {
+		t.Fatalf("failed to parse query: %v", err)
+	}
+	if _, err := query.Eval(map[string]any{"data": map[string]any{"slug": "a"}}); err == nil {
+		t.Fatal("expected selecting a field from a string to fail")
+	}
+}
+
+func TestPrintRaw(t *testing.T) {
+	var out bytes.Buffer
+	for _, value := range []any{"https://geda.vn/a.png", float64(12), []any{"a", "b"}, map[string]any{"id": float64(1)}} {
+		if err := printRaw(&out, value, false); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	expected := "https://geda.vn/a.png\n12\na\nb\n{\"id\":1}\n"
+	if out.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, out.String())
+	}
+}
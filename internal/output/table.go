@@ -0,0 +1,290 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+const (
+	columnGap      = "  "
+	minColumnWidth = 6
+	ellipsis       = "…"
+)
+
+// defaultColumns are the table columns shown for each resource when
+// --columns is not given.
+var defaultColumns = map[string][]string{
+	"post":     {"slug", "title", "status", "published_at", "category"},
+	"page":     {"slug", "title", "status", "published_at"},
+	"category": {"slug", "name", "parent"},
+	"tag":      {"slug", "name"},
+	"product":  {"slug", "name", "status", "price"},
+	"settings": {"key", "value"},
+}
+
+// fallbackLocale is used for bilingual fields missing the selected locale.
+const fallbackLocale = "vi"
+
+func printTable(w io.Writer, data any, opts Options) error {
+	rows, single := tableRows(data)
+
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = defaultColumns[opts.Resource]
+	}
+
+	// Responses that are not resources, such as health or config resolve,
+	// read better as a list of fields than as one very wide row.
+	if len(columns) == 0 && single {
+		return printFields(w, rows[0], opts)
+	}
+	if len(columns) == 0 {
+		columns = scalarKeys(rows)
+	}
+
+	cells := make([][]string, 0, len(rows)+1)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.ToUpper(column)
+	}
+	cells = append(cells, header)
+
+	for _, row := range rows {
+		line := make([]string, len(columns))
+		for i, column := range columns {
+			line[i] = cellText(lookup(row, column), opts.Locale)
+		}
+		cells = append(cells, line)
+	}
+
+	writeCells(w, cells, fitWidths(cells, terminalWidth()))
+
+	return nil
+}
+
+func printFields(w io.Writer, row map[string]any, opts Options) error {
+	keys := make([]string, 0, len(row))
+	for key := range row {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	cells := [][]string{{"FIELD", "VALUE"}}
+	for _, key := range keys {
+		cells = append(cells, []string{key, cellText(row[key], opts.Locale)})
+	}
+
+	writeCells(w, cells, fitWidths(cells, terminalWidth()))
+
+	return nil
+}
+
+// tableRows extracts the records to show: the items of a list response,
+// the record of a single-item response, or the response itself. single
+// reports whether there is exactly one record that is not part of a list.
+func tableRows(data any) ([]map[string]any, bool) {
+	response := toMap(data)
+
+	switch typed := response["data"].(type) {
+	case []any:
+		rows := make([]map[string]any, 0, len(typed))
+		for _, item := range typed {
+			if row, ok := item.(map[string]any); ok {
+				rows = append(rows, row)
+			} else {
+				rows = append(rows, map[string]any{"value": item})
+			}
+		}
+
+		return rows, false
+	case map[string]any:
+		return []map[string]any{typed}, true
+	default:
+		return []map[string]any{response}, true
+	}
+}
+
+func toMap(data any) map[string]any {
+	if typed, ok := toGeneric(data).(map[string]any); ok {
+		return typed
+	}
+
+	return map[string]any{"value": data}
+}
+
+func scalarKeys(rows []map[string]any) []string {
+	if len(rows) == 0 {
+		return []string{"value"}
+	}
+
+	keys := []string{}
+	for key, value := range rows[0] {
+		switch value.(type) {
+		case map[string]any, []any:
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// lookup resolves a dotted path such as "category.slug" or "title.en".
+func lookup(value any, path string) any {
+	for _, part := range strings.Split(path, ".") {
+		switch typed := value.(type) {
+		case map[string]any:
+			value = typed[part]
+		case []any:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(typed) {
+				return nil
+			}
+			value = typed[index]
+		default:
+			return nil
+		}
+	}
+
+	return value
+}
+
+// cellText renders a value for a table cell. Bilingual objects show the
+// selected locale and related records show their name or slug.
+func cellText(value any, locale string) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return singleLine(typed)
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(typed)
+	case map[string]any:
+		if text, ok := localized(typed, locale); ok {
+			return text
+		}
+		for _, key := range []string{"name", "title", "slug"} {
+			if item, ok := typed[key]; ok {
+				return cellText(item, locale)
+			}
+		}
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(encoded)
+}
+
+func localized(value map[string]any, locale string) (string, bool) {
+	for _, key := range []string{locale, fallbackLocale} {
+		if text, ok := value[key].(string); ok && key != "" {
+			return singleLine(text), true
+		}
+	}
+
+	return "", false
+}
+
+func singleLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func terminalWidth() int {
+	fd := int(os.Stdout.Fd())
+	if term.IsTerminal(fd) {
+		if width, _, err := term.GetSize(fd); err == nil && width > 0 {
+			return width
+		}
+	}
+
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+
+	return 0
+}
+
+// ColorEnabled reports whether ANSI colors may be written to file: it must
+// be a terminal and NO_COLOR must be unset.
+func ColorEnabled(file *os.File) bool {
+	return os.Getenv("NO_COLOR") == "" && term.IsTerminal(int(file.Fd()))
+}
+
+// fitWidths returns the width of every column, shrinking the widest columns
+// until the table fits maxWidth. A maxWidth of 0 means no limit.
+func fitWidths(cells [][]string, maxWidth int) []int {
+	widths := make([]int, len(cells[0]))
+	for _, row := range cells {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	if maxWidth <= 0 {
+		return widths
+	}
+
+	total := len(columnGap) * (len(widths) - 1)
+	for _, width := range widths {
+		total += width
+	}
+
+	for total > maxWidth {
+		widest := 0
+		for i, width := range widths {
+			if width > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			break
+		}
+
+		widths[widest]--
+		total--
+	}
+
+	return widths
+}
+
+func writeCells(w io.Writer, cells [][]string, widths []int) {
+	for _, row := range cells {
+		parts := make([]string, len(row))
+		for i, cell := range row {
+			cell = truncate(cell, widths[i])
+			if i < len(row)-1 {
+				cell += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			}
+			parts[i] = cell
+		}
+
+		fmt.Fprintln(w, strings.TrimRight(strings.Join(parts, columnGap), " "))
+	}
+}
+
+func truncate(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	if width <= 1 {
+		return ellipsis
+	}
+
+	runes := []rune(text)
+
+	return string(runes[:width-1]) + ellipsis
+}
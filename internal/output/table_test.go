@@ -0,0 +1,116 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrintTableUsesResourceColumnsAndLocale(t *testing.T) {
+	t.Setenv("COLUMNS", "")
+
+	response := map[string]any{
+		"data": []any{
+			map[string]any{
+				"slug":         "xin-chao",
+				"title":        map[string]any{"vi": "Xin chào", "en": "Hello"},
+				"status":       "published",
+				"published_at": "2026-01-02T03:04:05Z",
+				"category":     map[string]any{"slug": "tin-tuc", "name": map[string]any{"vi": "Tin tức", "en": "News"}},
+			},
+			map[string]any{
+				"slug":   "draft",
+				"title":  map[string]any{"vi": "Bản nháp"},
+				"status": "draft",
+			},
+		},
+		"meta": map[string]any{"total": 2},
+	}
+
+	var out bytes.Buffer
+	if err := printTable(&out, response, Options{Format: FormatTable, Resource: "post", Locale: "en"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got %q", out.String())
+	}
+	if strings.Join(strings.Fields(lines[0]), " ") != "SLUG TITLE STATUS PUBLISHED_AT CATEGORY" {
+		t.Fatalf("unexpected header %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "Hello") || !strings.Contains(lines[1], "News") {
+		t.Fatalf("expected English title and category, got %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "Bản nháp") {
+		t.Fatalf("expected Vietnamese fallback for missing locale, got %q", lines[2])
+	}
+	if strings.Index(lines[1], "published") != strings.Index(lines[0], "STATUS") {
+		t.Fatalf("expected aligned columns, got\n%s", out.String())
+	}
+}
+
+func TestPrintTableWithDottedColumns(t *testing.T) {
+	t.Setenv("COLUMNS", "")
+
+	response := map[string]any{
+		"data": map[string]any{"id": float64(12), "title": map[string]any{"vi": "Tiêu đề", "en": "Title"}},
+	}
+
+	var out bytes.Buffer
+	if err := printTable(&out, response, Options{Columns: []string{"id", "title.vi"}, Locale: "en"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "ID  TITLE.VI\n12  Tiêu đề\n"
+	if out.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestPrintTableShowsFieldsForPlainResponses(t *testing.T) {
+	t.Setenv("COLUMNS", "")
+
+	var out bytes.Buffer
+	if err := printTable(&out, map[string]any{"status": "ok", "version": "1.2"}, Options{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "FIELD    VALUE\nstatus   ok\nversion  1.2\n"
+	if out.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestPrintTableFitsTerminalWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "30")
+
+	response := map[string]any{
+		"data": []any{
+			map[string]any{"slug": "short", "name": strings.Repeat("very long name ", 5)},
+		},
+	}
+
+	var out bytes.Buffer
+	if err := printTable(&out, response, Options{Resource: "tag"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(out.String(), "\n"), "\n") {
+		if width := len([]rune(line)); width > 30 {
+			t.Fatalf("expected lines to fit 30 columns, got %d: %q", width, line)
+		}
+	}
+	if !strings.Contains(out.String(), "…") {
+		t.Fatalf("expected truncated cell, got %q", out.String())
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat("TABLE"); err != nil || format != FormatTable {
+		t.Fatalf("expected table format, got %q (%v)", format, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Fatal("expected unknown format to fail")
+	}
+}
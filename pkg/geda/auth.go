@@ -0,0 +1,77 @@
+package geda
+
+import (
+	"context"
+	"strings"
+
+	"geda-cli/internal/httpclient"
+)
+
+type AuthService struct {
+	http *httpclient.Client
+}
+
+type LoginRequest struct {
+	Email      string
+	Password   string
+	DeviceName string
+	// OTP or RecoveryCode answers a two-factor challenge.
+	OTP          string
+	RecoveryCode string
+}
+
+type LoginResult struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type,omitempty"`
+	User        *User  `json:"user,omitempty"`
+}
+
+// Login exchanges credentials for an access token. Use a client without a
+// token, then create a new client with LoginResult.AccessToken.
+func (s *AuthService) Login(ctx context.Context, request LoginRequest) (*LoginResult, *Response, error) {
+	body, err := s.http.Post(ctx, "/api/v1/auth/login", map[string]any{
+		"email":         request.Email,
+		"password":      request.Password,
+		"device_name":   request.DeviceName,
+		"otp":           emptyToNil(request.OTP),
+		"recovery_code": emptyToNil(request.RecoveryCode),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &LoginResult{}
+	if err := decode(body, result); err != nil {
+		return nil, nil, err
+	}
+
+	return result, newResponse(body), nil
+}
+
+// Logout revokes the client's access token.
+func (s *AuthService) Logout(ctx context.Context) (*Response, error) {
+	body, err := s.http.Post(ctx, "/api/v1/auth/logout", map[string]any{})
+	if err != nil {
+		return nil, err
+	}
+
+	return newResponse(body), nil
+}
+
+// Me returns the user the access token belongs to.
+func (s *AuthService) Me(ctx context.Context) (*User, *Response, error) {
+	body, err := s.http.Get(ctx, "/api/v1/auth/me")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return decodeItem[User](body)
+}
+
+func emptyToNil(value string) any {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	return value
+}
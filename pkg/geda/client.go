@@ -0,0 +1,170 @@
+// Package geda is a Go client for the geda-web API.
+//
+//	client := geda.NewClient("https://geda.vn", token)
+//	post, _, err := client.Posts.Get(ctx, "xin-chao")
+//
+// Every call returns the typed record together with a Response holding the
+// decoded JSON body, so callers that need fields the structs do not model
+// can still reach them.
+package geda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"geda-cli/internal/httpclient"
+)
+
+type (
+	Option      = httpclient.Option
+	RetryPolicy = httpclient.RetryPolicy
+	Timeouts    = httpclient.Timeouts
+	Cassette    = httpclient.Cassette
+	APIError    = httpclient.APIError
+)
+
+var (
+	WithRetryPolicy = httpclient.WithRetryPolicy
+	WithTimeouts    = httpclient.WithTimeouts
+	WithCassette    = httpclient.WithCassette
+
+	// WithHeader adds a header to every request made with the returned
+	// context, such as If-Unmodified-Since on a write.
+	WithHeader = httpclient.WithHeader
+
+	DefaultRetryPolicy = httpclient.DefaultRetryPolicy
+	DefaultTimeouts    = httpclient.DefaultTimeouts
+)
+
+// Errors matched by errors.Is on an *APIError.
+var (
+	ErrUnauthorized = httpclient.ErrUnauthorized
+	ErrForbidden    = httpclient.ErrForbidden
+	ErrNotFound     = httpclient.ErrNotFound
+	ErrConflict     = httpclient.ErrConflict
+	ErrValidation   = httpclient.ErrValidation
+	ErrRateLimited  = httpclient.ErrRateLimited
+)
+
+// WithLogger traces requests and responses to w with secrets redacted.
+func WithLogger(w io.Writer) Option {
+	return httpclient.WithLogger(w)
+}
+
+// WithCurl writes an equivalent curl command for every request to w.
+func WithCurl(w io.Writer) Option {
+	return httpclient.WithCurl(w)
+}
+
+type Client struct {
+	http *httpclient.Client
+
+	Auth       *AuthService
+	Posts      *ResourceService[Post]
+	Categories *ResourceService[Category]
+	Tags       *ResourceService[Tag]
+	Pages      *ResourceService[Page]
+	Products   *ResourceService[Product]
+	Media      *MediaService
+	Settings   *SettingsService
+}
+
+// NewClient returns a client for the API at baseURL. accessToken may be
+// empty for calls that do not need authentication, such as Health and
+// Auth.Login.
+func NewClient(baseURL string, accessToken string, options ...Option) *Client {
+	http := httpclient.New(baseURL, accessToken, options...)
+
+	return &Client{
+		http:       http,
+		Auth:       &AuthService{http: http},
+		Posts:      newResourceService[Post](http, "posts"),
+		Categories: newResourceService[Category](http, "categories"),
+		Tags:       newResourceService[Tag](http, "tags"),
+		Pages:      newResourceService[Page](http, "pages"),
+		Products:   newResourceService[Product](http, "products"),
+		Media:      &MediaService{http: http},
+		Settings:   &SettingsService{http: http},
+	}
+}
+
+// Resource returns an untyped service for the collection at
+// /api/v1/<plural>, for code that handles resources by name.
+func (c *Client) Resource(plural string) *ResourceService[map[string]any] {
+	return newResourceService[map[string]any](c.http, plural)
+}
+
+func (c *Client) BaseURL() string {
+	return c.http.BaseURL()
+}
+
+// Health reports whether the API is up. It does not need a token.
+func (c *Client) Health(ctx context.Context) (*Health, *Response, error) {
+	body, err := c.http.Get(ctx, "/api/v1/health")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	health := &Health{}
+	if err := decode(body, health); err != nil {
+		return nil, nil, err
+	}
+
+	return health, newResponse(body), nil
+}
+
+// Response is a decoded API response.
+type Response struct {
+	// Body is the JSON body exactly as the API sent it.
+	Body    map[string]any
+	Message string
+	// Meta is set for paginated listings.
+	Meta *PageMeta
+}
+
+type PageMeta struct {
+	CurrentPage int `json:"current_page"`
+	LastPage    int `json:"last_page"`
+	PerPage     int `json:"per_page"`
+	Total       int `json:"total"`
+}
+
+func newResponse(body map[string]any) *Response {
+	response := &Response{Body: body}
+	response.Message, _ = body["message"].(string)
+
+	if meta, ok := body["meta"].(map[string]any); ok {
+		response.Meta = &PageMeta{}
+		if err := decode(meta, response.Meta); err != nil {
+			response.Meta = nil
+		}
+	}
+
+	return response
+}
+
+// decode converts a generic JSON value into target.
+func decode(value any, target any) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(encoded, target); err != nil {
+		return fmt.Errorf("unexpected response shape: %w", err)
+	}
+
+	return nil
+}
+
+// decodeData decodes the data member of an API response into target.
+func decodeData(body map[string]any, target any) error {
+	data, ok := body["data"]
+	if !ok || data == nil {
+		return fmt.Errorf("unexpected response shape: missing data")
+	}
+
+	return decode(data, target)
+}
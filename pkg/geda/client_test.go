@@ -0,0 +1,164 @@
+package geda
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"geda-cli/internal/mockserver"
+)
+
+func newMockClient(t *testing.T) (*Client, *mockserver.Server) {
+	t.Helper()
+
+	mock := mockserver.New()
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+
+	login, _, err := NewClient(server.URL, "").Auth.Login(context.Background(), LoginRequest{
+		Email:      "admin@example.com",
+		Password:   "password",
+		DeviceName: "sdk-test",
+	})
+	if err != nil {
+		t.Fatalf("failed to log in: %v", err)
+	}
+	if login.User == nil || login.User.Email != "admin@example.com" {
+		t.Fatalf("expected login user, got %#v", login.User)
+	}
+
+	return NewClient(server.URL, login.AccessToken, WithRetryPolicy(RetryPolicy{MaxAttempts: 1})), mock
+}
+
+func TestPostsUpsertGetAndDelete(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newMockClient(t)
+
+	category, _, err := client.Categories.Create(ctx, Category{Slug: "tin-tuc", Name: Localized{"vi": "Tin tức", "en": "News"}})
+	if err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+
+	created, _, err := client.Posts.Upsert(ctx, "xin-chao", Post{
+		Slug:       "xin-chao",
+		Title:      Localized{"vi": "Xin chào", "en": "Hello"},
+		Status:     "draft",
+		CategoryID: category.ID,
+		Tags:       TagList{{ID: 3}, {ID: 5}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create post: %v", err)
+	}
+	if created.ID == 0 || created.Title.Get("en") != "Hello" || created.CategoryID != category.ID {
+		t.Fatalf("unexpected created post %#v", created)
+	}
+	if ids := created.Tags.IDs(); len(ids) != 2 || ids[1] != 5 {
+		t.Fatalf("expected tag ids to round-trip, got %#v", ids)
+	}
+
+	updated, response, err := client.Posts.Upsert(ctx, "xin-chao", map[string]any{"status": "published"})
+	if err != nil {
+		t.Fatalf("failed to update post: %v", err)
+	}
+	if updated.ID != created.ID || updated.Status != "published" {
+		t.Fatalf("expected the same post to be updated, got %#v", updated)
+	}
+	if response.Message == "" || response.Body["data"] == nil {
+		t.Fatalf("expected raw response body, got %#v", response)
+	}
+
+	fetched, _, err := client.Posts.Get(ctx, "xin-chao")
+	if err != nil {
+		t.Fatalf("failed to get post: %v", err)
+	}
+	if fetched.Title.Get("vi") != "Xin chào" || fetched.Status != "published" {
+		t.Fatalf("unexpected post %#v", fetched)
+	}
+
+	if _, err := client.Posts.Delete(ctx, "xin-chao"); err != nil {
+		t.Fatalf("failed to delete post: %v", err)
+	}
+
+	_, _, err = client.Posts.Get(ctx, "xin-chao")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestListPagesAndAll(t *testing.T) {
+	ctx := context.Background()
+	client, mock := newMockClient(t)
+
+	records := []map[string]any{}
+	for _, slug := range []string{"go", "cli", "web", "api", "sdk"} {
+		records = append(records, map[string]any{"slug": slug, "name": slug})
+	}
+	if err := mock.Seed("tags", records); err != nil {
+		t.Fatalf("failed to seed tags: %v", err)
+	}
+
+	tags, response, err := client.Tags.List(ctx, &ListOptions{PerPage: 2, Page: 2})
+	if err != nil {
+		t.Fatalf("failed to list tags: %v", err)
+	}
+	if len(tags) != 2 || tags[0].Slug != "web" || tags[0].Name.Get("en") != "web" {
+		t.Fatalf("unexpected page %#v", tags)
+	}
+	if response.Meta == nil || response.Meta.LastPage != 3 || response.Meta.Total != 5 {
+		t.Fatalf("unexpected meta %#v", response.Meta)
+	}
+
+	slugs := []string{}
+	for tag, err := range client.Tags.All(ctx, &ListOptions{PerPage: 2}) {
+		if err != nil {
+			t.Fatalf("failed to iterate tags: %v", err)
+		}
+		slugs = append(slugs, tag.Slug)
+	}
+	if len(slugs) != 5 || slugs[4] != "sdk" {
+		t.Fatalf("expected all 5 tags, got %#v", slugs)
+	}
+}
+
+func TestMediaAndSettings(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newMockClient(t)
+
+	filePath := filepath.Join(t.TempDir(), "cover.png")
+	if err := os.WriteFile(filePath, []byte("image"), 0o600); err != nil {
+		t.Fatalf("failed to write image: %v", err)
+	}
+
+	media, _, err := client.Media.Upload(ctx, filePath, Localized{"vi": "Ảnh bìa"})
+	if err != nil {
+		t.Fatalf("failed to upload: %v", err)
+	}
+	if media.URL == "" || media.AltText["vi"] != "Ảnh bìa" || media.Size != 5 {
+		t.Fatalf("unexpected media %#v", media)
+	}
+
+	if _, _, err := client.Settings.Set(ctx, "site_name", "GEDA"); err != nil {
+		t.Fatalf("failed to set setting: %v", err)
+	}
+
+	settings, _, err := client.Settings.List(ctx)
+	if err != nil {
+		t.Fatalf("failed to list settings: %v", err)
+	}
+	if len(settings) != 1 || settings[0].Key != "site_name" || settings[0].Value != "GEDA" {
+		t.Fatalf("unexpected settings %#v", settings)
+	}
+
+	user, _, err := client.Auth.Me(ctx)
+	if err != nil || user.Email != "admin@example.com" {
+		t.Fatalf("expected current user, got %#v (%v)", user, err)
+	}
+
+	health, _, err := client.Health(ctx)
+	if err != nil || health.Status != "ok" {
+		t.Fatalf("expected healthy API, got %#v (%v)", health, err)
+	}
+}
@@ -0,0 +1,41 @@
+package geda
+
+import (
+	"context"
+	"io"
+
+	"geda-cli/internal/httpclient"
+)
+
+type MediaService struct {
+	http *httpclient.Client
+}
+
+// Upload sends the file at filePath to the media library. Empty alt texts
+// are left out.
+func (s *MediaService) Upload(ctx context.Context, filePath string, altText Localized) (*Media, *Response, error) {
+	return s.UploadWithAltText(ctx, filePath, altText)
+}
+
+// UploadWithAltText is Upload with the alt text as a plain map.
+func (s *MediaService) UploadWithAltText(ctx context.Context, filePath string, altText map[string]string) (*Media, *Response, error) {
+	fields := map[string]string{}
+	for locale, text := range altText {
+		if text != "" {
+			fields["alt_text["+locale+"]"] = text
+		}
+	}
+
+	body, err := s.http.PostMultipartFile(ctx, "/api/v1/media", "file", filePath, fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return decodeItem[Media](body)
+}
+
+// Download writes the media file at link, an absolute URL or a path such as
+// /storage/uploads/a.png, to w.
+func (s *MediaService) Download(ctx context.Context, link string, w io.Writer) error {
+	return s.http.Download(ctx, link, w)
+}
@@ -0,0 +1,208 @@
+package geda
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// ID is a record id. It decodes from a JSON number as well as from a
+// numeric string such as "12", which some endpoints return.
+type ID int
+
+func (id *ID) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		*id = ID(number)
+
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("id must be a number or a string, got %s", data)
+	}
+	if text == "" {
+		*id = 0
+
+		return nil
+	}
+
+	number, err := strconv.Atoi(text)
+	if err != nil {
+		return fmt.Errorf("invalid id %q: %w", text, err)
+	}
+	*id = ID(number)
+
+	return nil
+}
+
+// Localized is a translated text field keyed by locale, such as
+// {"vi": ..., "en": ..., "ja": ...}. It also decodes from a plain string,
+// which fills vi and en.
+type Localized map[string]string
+
+// Get returns the text for locale, falling back to Vietnamese.
+func (l Localized) Get(locale string) string {
+	if text := l[locale]; text != "" {
+		return text
+	}
+
+	return l["vi"]
+}
+
+// UnmarshalJSON leaves l unchanged for null, so a field the server has
+// as null is not sent back as empty strings.
+func (l *Localized) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*l = Localized{"vi": text, "en": text}
+
+		return nil
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*l = values
+
+	return nil
+}
+
+type Post struct {
+	ID              ID        `json:"id,omitempty"`
+	Slug            string    `json:"slug"`
+	Title           Localized `json:"title,omitzero"`
+	Excerpt         Localized `json:"excerpt,omitzero"`
+	Body            Localized `json:"body,omitzero"`
+	MetaTitle       Localized `json:"meta_title,omitzero"`
+	MetaDescription Localized `json:"meta_description,omitzero"`
+	FeaturedImage   string    `json:"featured_image,omitempty"`
+	OGImage         string    `json:"og_image,omitempty"`
+	Status          string    `json:"status,omitempty"`
+	IsFeatured      *bool     `json:"is_featured,omitempty"`
+	PublishedAt     string    `json:"published_at,omitempty"`
+	ScheduledAt     string    `json:"scheduled_at,omitempty"`
+	CategoryID      ID        `json:"category_id,omitempty"`
+	Category        *Category `json:"category,omitempty"`
+	Tags            TagList   `json:"tags,omitempty"`
+	CreatedAt       string    `json:"created_at,omitempty"`
+	UpdatedAt       string    `json:"updated_at,omitempty"`
+}
+
+type Category struct {
+	ID          ID        `json:"id,omitempty"`
+	Slug        string    `json:"slug"`
+	Name        Localized `json:"name,omitzero"`
+	Description Localized `json:"description,omitzero"`
+	ParentID    *ID       `json:"parent_id,omitempty"`
+	CreatedAt   string    `json:"created_at,omitempty"`
+	UpdatedAt   string    `json:"updated_at,omitempty"`
+}
+
+type Tag struct {
+	ID        ID        `json:"id,omitempty"`
+	Slug      string    `json:"slug"`
+	Name      Localized `json:"name,omitzero"`
+	CreatedAt string    `json:"created_at,omitempty"`
+	UpdatedAt string    `json:"updated_at,omitempty"`
+}
+
+// TagList holds a post's tags. The API returns tag objects but expects tag
+// ids when saving, so it decodes both and encodes ids.
+type TagList []Tag
+
+func (t TagList) IDs() []ID {
+	ids := make([]ID, 0, len(t))
+	for _, tag := range t {
+		ids = append(ids, tag.ID)
+	}
+
+	return ids
+}
+
+func (t TagList) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.IDs())
+}
+
+func (t *TagList) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	tags := make(TagList, 0, len(items))
+	for _, item := range items {
+		var id ID
+		if err := json.Unmarshal(item, &id); err == nil {
+			tags = append(tags, Tag{ID: id})
+
+			continue
+		}
+
+		var tag Tag
+		if err := json.Unmarshal(item, &tag); err != nil {
+			return err
+		}
+		tags = append(tags, tag)
+	}
+
+	*t = tags
+
+	return nil
+}
+
+type Page struct {
+	ID              ID        `json:"id,omitempty"`
+	Slug            string    `json:"slug"`
+	Title           Localized `json:"title,omitzero"`
+	Body            Localized `json:"body,omitzero"`
+	MetaTitle       Localized `json:"meta_title,omitzero"`
+	MetaDescription Localized `json:"meta_description,omitzero"`
+	Status          string    `json:"status,omitempty"`
+	PublishedAt     string    `json:"published_at,omitempty"`
+	CreatedAt       string    `json:"created_at,omitempty"`
+	UpdatedAt       string    `json:"updated_at,omitempty"`
+}
+
+type Product struct {
+	ID          ID          `json:"id,omitempty"`
+	Slug        string      `json:"slug"`
+	Name        Localized   `json:"name,omitzero"`
+	Description Localized   `json:"description,omitzero"`
+	Price       json.Number `json:"price,omitempty"`
+	Image       string      `json:"image,omitempty"`
+	Status      string      `json:"status,omitempty"`
+	CreatedAt   string      `json:"created_at,omitempty"`
+	UpdatedAt   string      `json:"updated_at,omitempty"`
+}
+
+type Media struct {
+	ID      ID        `json:"id"`
+	URL     string    `json:"url"`
+	Path    string    `json:"path,omitempty"`
+	AltText Localized `json:"alt_text,omitzero"`
+	Size    int64     `json:"size,omitempty"`
+}
+
+type Setting struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
+type User struct {
+	ID    ID     `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type Health struct {
+	Status      string `json:"status"`
+	App         string `json:"app,omitempty"`
+	Environment string `json:"environment,omitempty"`
+}
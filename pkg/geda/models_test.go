@@ -0,0 +1,95 @@
+package geda
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLocalizedDecodesStringsAndObjects(t *testing.T) {
+	var tag Tag
+	if err := json.Unmarshal([]byte(`{"slug":"go","name":"Go"}`), &tag); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if tag.Name.Get("en") != "Go" || tag.Name.Get("vi") != "Go" {
+		t.Fatalf("expected plain string in both languages, got %#v", tag.Name)
+	}
+
+	var post Post
+	if err := json.Unmarshal([]byte(`{"slug":"a","title":{"vi":"Tiêu đề"},"tags":[{"id":1,"slug":"go"},7]}`), &post); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if post.Title.Get("en") != "Tiêu đề" {
+		t.Fatalf("expected Vietnamese fallback, got %q", post.Title.Get("en"))
+	}
+	if len(post.Tags) != 2 || post.Tags[0].Slug != "go" || post.Tags[1].ID != 7 {
+		t.Fatalf("unexpected tags %#v", post.Tags)
+	}
+}
+
+func TestPostEncodesOnlySetFields(t *testing.T) {
+	encoded, err := json.Marshal(Post{Slug: "a", Title: Localized{"vi": "A"}, Tags: TagList{{ID: 2}}})
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+
+	expected := `{"slug":"a","title":{"vi":"A"},"tags":[2]}`
+	if string(encoded) != expected {
+		t.Fatalf("expected %s, got %s", expected, encoded)
+	}
+}
+
+func TestModelsDecodeStringIDsAndEveryLocale(t *testing.T) {
+	var post Post
+	if err := json.Unmarshal([]byte(`{"id":"12","slug":"a","category_id":"3","title":{"vi":"A","en":"A","ja":"エー"},"tags":["7",{"id":"8"}]}`), &post); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if post.ID != 12 || post.CategoryID != 3 || post.Tags[0].ID != 7 || post.Tags[1].ID != 8 {
+		t.Fatalf("unexpected ids %#v", post)
+	}
+	if post.Title.Get("ja") != "エー" {
+		t.Fatalf("expected the ja title, got %#v", post.Title)
+	}
+
+	if err := json.Unmarshal([]byte(`{"id":"x"}`), &post); err == nil {
+		t.Fatal("expected an error for a non-numeric id")
+	}
+}
+
+func TestModelsEncodeExplicitFalseAndZero(t *testing.T) {
+	isFeatured := false
+	parentID := ID(0)
+
+	encoded, err := json.Marshal(Post{Slug: "a", IsFeatured: &isFeatured})
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	if string(encoded) != `{"slug":"a","is_featured":false}` {
+		t.Fatalf("unexpected post %s", encoded)
+	}
+
+	encoded, err = json.Marshal(Category{Slug: "b", ParentID: &parentID})
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	if string(encoded) != `{"slug":"b","parent_id":0}` {
+		t.Fatalf("unexpected category %s", encoded)
+	}
+}
+
+func TestPostRoundTripKeepsNullFieldsOut(t *testing.T) {
+	var post Post
+	if err := json.Unmarshal([]byte(`{"slug":"a","title":{"vi":"A"},"excerpt":null,"meta_title":null}`), &post); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if post.Excerpt != nil || post.MetaTitle != nil {
+		t.Fatalf("expected null fields to stay nil, got %#v and %#v", post.Excerpt, post.MetaTitle)
+	}
+
+	encoded, err := json.Marshal(post)
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	if string(encoded) != `{"slug":"a","title":{"vi":"A"}}` {
+		t.Fatalf("expected null fields to be left out, got %s", encoded)
+	}
+}
@@ -0,0 +1,228 @@
+package geda
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"geda-cli/internal/httpclient"
+)
+
+// ResourceService manages one collection under /api/v1, addressed by slug.
+// Payloads may be a T, a *T or a map[string]any; maps are sent as is, which
+// is how the CLI forwards JSON files untouched.
+type ResourceService[T any] struct {
+	http *httpclient.Client
+	path string
+}
+
+func newResourceService[T any](http *httpclient.Client, plural string) *ResourceService[T] {
+	return &ResourceService[T]{http: http, path: "/api/v1/" + plural}
+}
+
+// ListOptions filters a listing. Zero values are left out of the query.
+type ListOptions struct {
+	Page    int
+	PerPage int
+	Search  string
+	Status  string
+	Type    string
+}
+
+func (o *ListOptions) query() string {
+	if o == nil {
+		return ""
+	}
+
+	query := []string{}
+	if o.PerPage > 0 {
+		query = append(query, "per_page="+strconv.Itoa(o.PerPage))
+	}
+	if o.Page > 1 {
+		query = append(query, "page="+strconv.Itoa(o.Page))
+	}
+	if o.Search != "" {
+		query = append(query, "search="+queryEscape(o.Search))
+	}
+	if o.Status != "" {
+		query = append(query, "status="+queryEscape(o.Status))
+	}
+	if o.Type != "" {
+		query = append(query, "type="+queryEscape(o.Type))
+	}
+
+	if len(query) == 0 {
+		return ""
+	}
+
+	return "?" + strings.Join(query, "&")
+}
+
+func queryEscape(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+// List fetches one page.
+func (s *ResourceService[T]) List(ctx context.Context, opts *ListOptions) ([]T, *Response, error) {
+	body, err := s.http.Get(ctx, s.path+opts.query())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := []T{}
+	if err := decodeData(body, &items); err != nil {
+		return nil, nil, err
+	}
+
+	return items, newResponse(body), nil
+}
+
+// Pages walks the listing page by page, starting at opts.Page.
+func (s *ResourceService[T]) Pages(opts *ListOptions) *Pager[T] {
+	return &Pager[T]{pager: s.http.Pages(s.path + opts.query())}
+}
+
+// All yields every item of every page, stopping at the first error.
+func (s *ResourceService[T]) All(ctx context.Context, opts *ListOptions) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		pager := s.Pages(opts)
+		for pager.Next(ctx) {
+			items, err := pager.Items()
+			if err != nil {
+				var zero T
+				yield(zero, err)
+
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+
+		if err := pager.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+func (s *ResourceService[T]) Get(ctx context.Context, slug string) (*T, *Response, error) {
+	body, err := s.http.Get(ctx, s.itemPath(slug))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return decodeItem[T](body)
+}
+
+func (s *ResourceService[T]) Create(ctx context.Context, payload any) (*T, *Response, error) {
+	body, err := s.http.Post(ctx, s.path, payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return decodeItem[T](body)
+}
+
+func (s *ResourceService[T]) Update(ctx context.Context, slug string, payload any) (*T, *Response, error) {
+	body, err := s.http.Put(ctx, s.itemPath(slug), payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return decodeItem[T](body)
+}
+
+// Patch sends only the fields in payload. Servers without PATCH answer 405,
+// in which case callers can fall back to Update with the full record.
+func (s *ResourceService[T]) Patch(ctx context.Context, slug string, payload any) (*T, *Response, error) {
+	body, err := s.http.Patch(ctx, s.itemPath(slug), payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return decodeItem[T](body)
+}
+
+// Upsert updates the record with slug, or creates it when the API answers
+// 404.
+func (s *ResourceService[T]) Upsert(ctx context.Context, slug string, payload any) (*T, *Response, error) {
+	_, _, err := s.Get(ctx, slug)
+	if err == nil {
+		return s.Update(ctx, slug, payload)
+	}
+
+	if !errors.Is(err, ErrNotFound) {
+		return nil, nil, err
+	}
+
+	return s.Create(ctx, payload)
+}
+
+func (s *ResourceService[T]) Delete(ctx context.Context, slug string) (*Response, error) {
+	body, err := s.http.Delete(ctx, s.itemPath(slug))
+	if err != nil {
+		return nil, err
+	}
+
+	return newResponse(body), nil
+}
+
+func (s *ResourceService[T]) itemPath(slug string) string {
+	return s.path + "/" + slug
+}
+
+// decodeItem decodes the data object of body. Responses without one, such
+// as a bare message, decode to nil.
+func decodeItem[T any](body map[string]any) (*T, *Response, error) {
+	response := newResponse(body)
+	if body["data"] == nil {
+		return nil, response, nil
+	}
+
+	item := new(T)
+	if err := decodeData(body, item); err != nil {
+		return nil, nil, err
+	}
+
+	return item, response, nil
+}
+
+// Pager walks a paginated listing. Call Next until it returns false, then
+// check Err.
+type Pager[T any] struct {
+	pager *httpclient.Pager
+}
+
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	return p.pager.Next(ctx)
+}
+
+// Items decodes the items of the current page.
+func (p *Pager[T]) Items() ([]T, error) {
+	items := []T{}
+	if err := decode(p.RawItems(), &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// RawItems returns the items of the current page as decoded JSON.
+func (p *Pager[T]) RawItems() []any {
+	return httpclient.PageItems(p.pager.Page())
+}
+
+func (p *Pager[T]) Response() *Response {
+	return newResponse(p.pager.Page())
+}
+
+func (p *Pager[T]) Err() error {
+	return p.pager.Err()
+}
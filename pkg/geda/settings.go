@@ -0,0 +1,54 @@
+package geda
+
+import (
+	"context"
+	"sort"
+
+	"geda-cli/internal/httpclient"
+)
+
+type SettingsService struct {
+	http *httpclient.Client
+}
+
+// List returns every setting, sorted by key. It accepts both a list of
+// {key, value} objects and a single key-to-value object as data.
+func (s *SettingsService) List(ctx context.Context) ([]Setting, *Response, error) {
+	body, err := s.http.Get(ctx, "/api/v1/settings")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	settings := []Setting{}
+	if values, ok := body["data"].(map[string]any); ok {
+		for key, value := range values {
+			settings = append(settings, Setting{Key: key, Value: value})
+		}
+		sort.Slice(settings, func(i, j int) bool {
+			return settings[i].Key < settings[j].Key
+		})
+	} else if err := decodeData(body, &settings); err != nil {
+		return nil, nil, err
+	}
+
+	return settings, newResponse(body), nil
+}
+
+func (s *SettingsService) Get(ctx context.Context, key string) (*Setting, *Response, error) {
+	body, err := s.http.Get(ctx, "/api/v1/settings/"+key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return decodeItem[Setting](body)
+}
+
+// Set stores value, which is sent as JSON, under key.
+func (s *SettingsService) Set(ctx context.Context, key string, value any) (*Setting, *Response, error) {
+	body, err := s.http.Put(ctx, "/api/v1/settings/"+key, map[string]any{"value": value})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return decodeItem[Setting](body)
+}